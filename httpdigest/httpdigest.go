@@ -0,0 +1,305 @@
+// Package httpdigest implements HTTP Digest access authentication (RFC 2617, RFC 7616)
+// as a http.RoundTripper. This is the scheme monero-wallet-rpc uses when it is
+// started with --rpc-login.
+package httpdigest
+
+import (
+	"bytes"
+	"crypto/md5"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// ErrUnsupportedChallenge is returned when the server asks for digest
+// authentication with an algorithm or qop this package does not implement.
+var ErrUnsupportedChallenge = errors.New("httpdigest: unsupported digest challenge")
+
+// Transport is a http.RoundTripper which authenticates requests using HTTP Digest authentication.
+//
+// The last challenge received from the server is cached, so following requests are
+// authorized up front with an increasing nonce count (nc) instead of costing an extra
+// round trip. A challenge marked as stale is picked up and the request is sent again.
+type Transport struct {
+	Username string
+	Password string
+	// Transport is the underlying RoundTripper. If nil, http.DefaultTransport is used.
+	Transport http.RoundTripper
+
+	mu   sync.Mutex
+	chal *challenge
+	nc   uint32
+}
+
+// New returns a digest authentication transport for the given credentials
+// on top of http.DefaultTransport.
+func New(username, password string) *Transport {
+	return &Transport{
+		Username: username,
+		Password: password,
+	}
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, used, err := t.send(req, body)
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+
+	fresh, err := pickChallenge(resp.Header.Values("WWW-Authenticate"))
+	if err != nil {
+		return resp, nil
+	}
+	// Without a cached challenge the first attempt was sent anonymously. With one,
+	// it is only worth sending again if the server handed out a new or stale nonce.
+	if used != nil && !fresh.stale && fresh.nonce == used.nonce {
+		return resp, nil
+	}
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+
+	t.mu.Lock()
+	t.chal = fresh
+	t.nc = 0
+	t.mu.Unlock()
+
+	resp, _, err = t.send(req, body)
+	return resp, err
+}
+
+// send sends a copy of req, authorized with the cached challenge if there is one.
+// It returns the challenge the request was authorized with.
+func (t *Transport) send(req *http.Request, body []byte) (*http.Response, *challenge, error) {
+	r := req.Clone(req.Context())
+	if body != nil {
+		r.Body = io.NopCloser(bytes.NewReader(body))
+	}
+	chal, err := t.authorize(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	resp, err := t.transport().RoundTrip(r)
+	return resp, chal, err
+}
+
+func (t *Transport) transport() http.RoundTripper {
+	if t.Transport != nil {
+		return t.Transport
+	}
+	return http.DefaultTransport
+}
+
+// authorize sets the Authorization header of req answering the cached challenge,
+// using the next nonce count. It does nothing if no challenge was received yet.
+func (t *Transport) authorize(req *http.Request) (*challenge, error) {
+	t.mu.Lock()
+	chal := t.chal
+	t.nc++
+	nc := fmt.Sprintf("%08x", t.nc)
+	t.mu.Unlock()
+	if chal == nil {
+		return nil, nil
+	}
+
+	cnonce, err := newCnonce()
+	if err != nil {
+		return nil, err
+	}
+	h := chal.hash()
+	uri := req.URL.RequestURI()
+
+	ha1 := digest(h, t.Username+":"+chal.realm+":"+t.Password)
+	if strings.HasSuffix(chal.algorithm, "-sess") {
+		ha1 = digest(h, ha1+":"+chal.nonce+":"+cnonce)
+	}
+	ha2 := digest(h, req.Method+":"+uri)
+
+	var response string
+	if chal.qop == "" {
+		response = digest(h, ha1+":"+chal.nonce+":"+ha2)
+	} else {
+		response = digest(h, ha1+":"+chal.nonce+":"+nc+":"+cnonce+":"+chal.qop+":"+ha2)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `Digest username="%s", realm="%s", nonce="%s", uri="%s", algorithm=%s, response="%s"`,
+		quote(t.Username), quote(chal.realm), quote(chal.nonce), quote(uri), chal.algorithm, response)
+	if chal.qop != "" {
+		fmt.Fprintf(&b, `, qop=%s, nc=%s, cnonce="%s"`, chal.qop, nc, cnonce)
+	}
+	if chal.opaque != "" {
+		fmt.Fprintf(&b, `, opaque="%s"`, quote(chal.opaque))
+	}
+	req.Header.Set("Authorization", b.String())
+	return chal, nil
+}
+
+// readBody buffers the request body so it can be sent a second time.
+func readBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+	if req.GetBody != nil {
+		rc, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		defer rc.Close()
+		return io.ReadAll(rc)
+	}
+	defer req.Body.Close()
+	return io.ReadAll(req.Body)
+}
+
+func newCnonce() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+func digest(h func() hash.Hash, s string) string {
+	d := h()
+	io.WriteString(d, s)
+	return hex.EncodeToString(d.Sum(nil))
+}
+
+func quote(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s)
+}
+
+// challenge is a parsed WWW-Authenticate digest challenge.
+type challenge struct {
+	realm     string
+	nonce     string
+	opaque    string
+	algorithm string
+	qop       string
+	stale     bool
+}
+
+func (c *challenge) hash() func() hash.Hash {
+	if strings.HasPrefix(c.algorithm, "SHA-256") {
+		return sha256.New
+	}
+	return md5.New
+}
+
+// algorithmRank orders the supported algorithms, strongest last.
+var algorithmRank = map[string]int{
+	"MD5":          1,
+	"MD5-sess":     2,
+	"SHA-256":      3,
+	"SHA-256-sess": 4,
+}
+
+// pickChallenge returns the strongest supported digest challenge among the
+// WWW-Authenticate header values.
+func pickChallenge(headers []string) (*challenge, error) {
+	var best *challenge
+	for _, h := range headers {
+		c, err := parseChallenge(h)
+		if err != nil {
+			continue
+		}
+		if best == nil || algorithmRank[c.algorithm] > algorithmRank[best.algorithm] {
+			best = c
+		}
+	}
+	if best == nil {
+		return nil, ErrUnsupportedChallenge
+	}
+	return best, nil
+}
+
+func parseChallenge(header string) (*challenge, error) {
+	const prefix = "digest "
+	if len(header) < len(prefix) || !strings.EqualFold(header[:len(prefix)], prefix) {
+		return nil, ErrUnsupportedChallenge
+	}
+	params := parseParams(header[len(prefix):])
+
+	c := &challenge{
+		realm:     params["realm"],
+		nonce:     params["nonce"],
+		opaque:    params["opaque"],
+		algorithm: "MD5",
+		stale:     strings.EqualFold(params["stale"], "true"),
+	}
+	if alg, ok := params["algorithm"]; ok {
+		c.algorithm = ""
+		for name := range algorithmRank {
+			if strings.EqualFold(alg, name) {
+				c.algorithm = name
+			}
+		}
+		if c.algorithm == "" {
+			return nil, ErrUnsupportedChallenge
+		}
+	}
+	if qop, ok := params["qop"]; ok {
+		for _, q := range strings.Split(qop, ",") {
+			if strings.TrimSpace(q) == "auth" {
+				c.qop = "auth"
+			}
+		}
+		if c.qop == "" {
+			return nil, ErrUnsupportedChallenge
+		}
+	}
+	if c.nonce == "" {
+		return nil, ErrUnsupportedChallenge
+	}
+	return c, nil
+}
+
+// parseParams splits a comma separated list of key=value or key="quoted value" pairs.
+func parseParams(s string) map[string]string {
+	params := make(map[string]string)
+	for {
+		s = strings.TrimLeft(s, " \t,")
+		if s == "" {
+			return params
+		}
+		eq := strings.IndexByte(s, '=')
+		if eq < 0 {
+			return params
+		}
+		key := strings.ToLower(strings.TrimSpace(s[:eq]))
+		s = strings.TrimLeft(s[eq+1:], " \t")
+
+		var val strings.Builder
+		if strings.HasPrefix(s, `"`) {
+			i := 1
+			for ; i < len(s) && s[i] != '"'; i++ {
+				if s[i] == '\\' && i+1 < len(s) {
+					i++
+				}
+				val.WriteByte(s[i])
+			}
+			s = s[min(i+1, len(s)):]
+		} else {
+			end := strings.IndexByte(s, ',')
+			if end < 0 {
+				end = len(s)
+			}
+			val.WriteString(strings.TrimSpace(s[:end]))
+			s = s[end:]
+		}
+		params[key] = val.String()
+	}
+}
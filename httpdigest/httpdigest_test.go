@@ -0,0 +1,132 @@
+package httpdigest
+
+import (
+	"crypto/md5"
+	"crypto/sha256"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// digestServer is a minimal digest authenticating server.
+type digestServer struct {
+	algorithm string
+
+	mu        sync.Mutex
+	nonce     string
+	lastNC    string
+	nonces    int
+	anonymous int
+	ncs       []string
+}
+
+func (s *digestServer) challenge(w http.ResponseWriter, stale bool) {
+	s.nonces++
+	s.nonce = fmt.Sprintf("nonce%d", s.nonces)
+	s.lastNC = ""
+	w.Header().Add("WWW-Authenticate", fmt.Sprintf(`Digest qop="auth",algorithm=MD5,realm="monero-rpc",nonce="%s",stale=%v`, s.nonce, stale))
+	if s.algorithm != "MD5" {
+		w.Header().Add("WWW-Authenticate", fmt.Sprintf(`Digest qop="auth",algorithm=%s,realm="monero-rpc",nonce="%s",stale=%v`, s.algorithm, s.nonce, stale))
+	}
+	w.WriteHeader(http.StatusUnauthorized)
+}
+
+func (s *digestServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	auth := r.Header.Get("Authorization")
+	if auth == "" {
+		s.anonymous++
+		s.challenge(w, false)
+		return
+	}
+	p := parseParams(strings.TrimPrefix(auth, "Digest "))
+	if p["nonce"] != s.nonce {
+		s.challenge(w, true)
+		return
+	}
+	h := md5.New
+	if p["algorithm"] == "SHA-256" {
+		h = sha256.New
+	}
+	if p["algorithm"] != s.algorithm {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	ha1 := digest(h, p["username"]+":monero-rpc:testpass")
+	ha2 := digest(h, r.Method+":"+p["uri"])
+	want := digest(h, ha1+":"+p["nonce"]+":"+p["nc"]+":"+p["cnonce"]+":"+p["qop"]+":"+ha2)
+	if p["response"] != want || p["nc"] <= s.lastNC {
+		s.challenge(w, false)
+		return
+	}
+	s.lastNC = p["nc"]
+	s.ncs = append(s.ncs, p["nc"])
+	w.Write([]byte("ok"))
+}
+
+func TestTransport(t *testing.T) {
+	for _, alg := range []string{"MD5", "SHA-256"} {
+		t.Run(alg, func(t *testing.T) {
+			s := &digestServer{algorithm: alg}
+			srv := httptest.NewServer(s)
+			defer srv.Close()
+			cl := &http.Client{Transport: New("test", "testpass")}
+
+			for i := 0; i < 3; i++ {
+				resp, err := cl.Post(srv.URL+"/json_rpc", "application/json", strings.NewReader(`{}`))
+				assert.NoError(t, err)
+				assert.Equal(t, http.StatusOK, resp.StatusCode)
+				resp.Body.Close()
+			}
+			// only the first request is sent without credentials, the nonce is reused afterwards
+			assert.Equal(t, 1, s.anonymous)
+			assert.Equal(t, []string{"00000001", "00000002", "00000003"}, s.ncs)
+
+			// a stale nonce is replaced transparently
+			s.mu.Lock()
+			s.nonce = "expired"
+			s.mu.Unlock()
+			resp, err := cl.Post(srv.URL+"/json_rpc", "application/json", strings.NewReader(`{}`))
+			assert.NoError(t, err)
+			assert.Equal(t, http.StatusOK, resp.StatusCode)
+			resp.Body.Close()
+		})
+	}
+}
+
+func TestTransportWrongPassword(t *testing.T) {
+	s := &digestServer{algorithm: "MD5"}
+	srv := httptest.NewServer(s)
+	defer srv.Close()
+	cl := &http.Client{Transport: New("test", "wrong")}
+
+	resp, err := cl.Get(srv.URL)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	resp.Body.Close()
+}
+
+func TestParseChallenge(t *testing.T) {
+	c, err := parseChallenge(`Digest realm="a \"b\", c", qop="auth,auth-int", nonce="n", algorithm=SHA-256-sess, stale=TRUE, opaque="o"`)
+	assert.NoError(t, err)
+	assert.Equal(t, &challenge{
+		realm:     `a "b", c`,
+		nonce:     "n",
+		opaque:    "o",
+		algorithm: "SHA-256-sess",
+		qop:       "auth",
+		stale:     true,
+	}, c)
+
+	_, err = parseChallenge(`Digest nonce="n", algorithm=SHA-512`)
+	assert.Equal(t, ErrUnsupportedChallenge, err)
+	_, err = parseChallenge(`Basic realm="x"`)
+	assert.Equal(t, ErrUnsupportedChallenge, err)
+}
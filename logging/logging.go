@@ -0,0 +1,97 @@
+// Package logging logs monero-wallet-rpc calls with log/slog.
+//
+// Every call is logged with its method, duration and outcome by an interceptor:
+//
+//	client := wallet.New(wallet.Config{
+//		Address:      "http://127.0.0.1:6061/json_rpc",
+//		Interceptors: []wallet.Interceptor{logging.Interceptor(slog.Default())},
+//	})
+//
+// Request and response payloads are only logged with WithPayloads, and then go
+// through wallet.Redact, which masks passwords, keys, seeds and other secrets.
+package logging
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/omani/go-monero-rpc-client/wallet"
+)
+
+// Option configures the interceptor.
+type Option func(*config)
+
+type config struct {
+	payloads     bool
+	level        slog.Level
+	failureLevel slog.Level
+}
+
+// WithPayloads logs the redacted params and result of each call.
+func WithPayloads() Option {
+	return func(c *config) {
+		c.payloads = true
+	}
+}
+
+// WithLevels sets the levels successful and failed calls are logged at.
+// Defaults to slog.LevelDebug and slog.LevelWarn.
+func WithLevels(success, failure slog.Level) Option {
+	return func(c *config) {
+		c.level = success
+		c.failureLevel = failure
+	}
+}
+
+// Interceptor returns a wallet.Interceptor logging every call to logger.
+func Interceptor(logger *slog.Logger, opts ...Option) wallet.Interceptor {
+	cfg := &config{
+		level:        slog.LevelDebug,
+		failureLevel: slog.LevelWarn,
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	return func(ctx context.Context, method string, params, result interface{}, next wallet.Invoker) error {
+		start := time.Now()
+		err := next(ctx, method, params, result)
+
+		level := cfg.level
+		attrs := []slog.Attr{
+			slog.String("method", method),
+			slog.Duration("duration", time.Since(start)),
+		}
+		if err != nil {
+			level = cfg.failureLevel
+			attrs = append(attrs, slog.String("outcome", "error"), slog.String("error", err.Error()))
+			attrs = append(attrs, errorAttrs(err)...)
+		} else {
+			attrs = append(attrs, slog.String("outcome", "ok"))
+		}
+		if !logger.Enabled(ctx, level) {
+			return err
+		}
+		if cfg.payloads {
+			attrs = append(attrs, slog.Any("params", wallet.Redact(params)))
+			if err == nil {
+				attrs = append(attrs, slog.Any("result", wallet.Redact(result)))
+			}
+		}
+		logger.LogAttrs(ctx, level, "monero-wallet-rpc call", attrs...)
+		return err
+	}
+}
+
+func errorAttrs(err error) []slog.Attr {
+	if isWalletError, werr := wallet.GetWalletError(err); isWalletError {
+		return []slog.Attr{slog.Int("error_code", int(werr.Code))}
+	}
+	var herr *wallet.HTTPError
+	if errors.As(err, &herr) {
+		return []slog.Attr{slog.Int("http_status", herr.StatusCode)}
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/omani/go-monero-rpc-client/wallet"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInterceptor(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID     uint64 `json:"id"`
+			Method string `json:"method"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		resp := wallet.H{"jsonrpc": "2.0", "id": req.ID}
+		switch req.Method {
+		case "query_key":
+			resp["result"] = wallet.H{"key": "abandon abandon ability"}
+		default:
+			resp["error"] = wallet.H{"code": wallet.ErrUnknown, "message": "Failed to open wallet"}
+		}
+		json.NewEncoder(w).Encode(resp)
+	}))
+	defer srv.Close()
+
+	buf := &bytes.Buffer{}
+	logger := slog.New(slog.NewJSONHandler(buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	cl := wallet.New(wallet.Config{
+		Address:      srv.URL,
+		Interceptors: []wallet.Interceptor{Interceptor(logger, WithPayloads())},
+	})
+
+	_, err := cl.QueryKey(&wallet.RequestQueryKey{KeyType: string(wallet.QueryKeyMnemonic)})
+	assert.NoError(t, err)
+	err = cl.OpenWallet(&wallet.RequestOpenWallet{Filename: "w", Password: "hunter2"})
+	assert.Error(t, err)
+
+	out := buf.String()
+	assert.NotContains(t, out, "abandon")
+	assert.NotContains(t, out, "hunter2")
+
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	assert.Len(t, lines, 2)
+
+	var rec map[string]interface{}
+	assert.NoError(t, json.Unmarshal([]byte(lines[0]), &rec))
+	assert.Equal(t, "DEBUG", rec["level"])
+	assert.Equal(t, "query_key", rec["method"])
+	assert.Equal(t, "ok", rec["outcome"])
+	assert.Equal(t, map[string]interface{}{"key": wallet.Redacted}, rec["result"])
+
+	assert.NoError(t, json.Unmarshal([]byte(lines[1]), &rec))
+	assert.Equal(t, "WARN", rec["level"])
+	assert.Equal(t, "open_wallet", rec["method"])
+	assert.Equal(t, "error", rec["outcome"])
+	assert.Equal(t, float64(wallet.ErrUnknown), rec["error_code"])
+	assert.Equal(t, map[string]interface{}{"filename": "w", "password": wallet.Redacted}, rec["params"])
+}
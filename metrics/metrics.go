@@ -0,0 +1,238 @@
+// Package metrics exports Prometheus metrics of monero-wallet-rpc calls.
+//
+// Metrics are collected by an interceptor, so every call of a wallet.Client is
+// counted without wrapping its methods:
+//
+//	m := metrics.New(metrics.Opts{})
+//	prometheus.MustRegister(m)
+//	client := wallet.New(wallet.Config{
+//		Address:      "http://127.0.0.1:6061/json_rpc",
+//		Interceptors: []wallet.Interceptor{m.Interceptor()},
+//	})
+package metrics
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/omani/go-monero-rpc-client/wallet"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Opts configures the exported metrics.
+type Opts struct {
+	// Namespace prefixes all metric names. Defaults to "monero".
+	Namespace string
+	// ConstLabels are added to all metrics, eg. to tell several wallets apart.
+	ConstLabels prometheus.Labels
+	// Buckets of the call duration histogram. Defaults to prometheus.DefBuckets.
+	Buckets []float64
+}
+
+// Metrics collects metrics of monero-wallet-rpc calls. It implements prometheus.Collector.
+type Metrics struct {
+	calls           *prometheus.CounterVec
+	duration        *prometheus.HistogramVec
+	inFlight        *prometheus.GaugeVec
+	balance         *prometheus.GaugeVec
+	unlockedBalance *prometheus.GaugeVec
+	height          prometheus.Gauge
+	cacheHits       *prometheus.Desc
+	cacheMisses     *prometheus.Desc
+
+	mu     sync.Mutex
+	caches []*wallet.Cache
+}
+
+// New returns a new set of metrics. It has to be registered with a prometheus.Registerer.
+func New(opts Opts) *Metrics {
+	ns := opts.Namespace
+	if ns == "" {
+		ns = "monero"
+	}
+	buckets := opts.Buckets
+	if buckets == nil {
+		buckets = prometheus.DefBuckets
+	}
+	return &Metrics{
+		calls: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace:   ns,
+			Subsystem:   "wallet_rpc",
+			Name:        "calls_total",
+			Help:        "Number of monero-wallet-rpc calls by method, wallet error code and HTTP status.",
+			ConstLabels: opts.ConstLabels,
+		}, []string{"method", "error_code", "http_status"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace:   ns,
+			Subsystem:   "wallet_rpc",
+			Name:        "call_duration_seconds",
+			Help:        "Duration of monero-wallet-rpc calls by method.",
+			ConstLabels: opts.ConstLabels,
+			Buckets:     buckets,
+		}, []string{"method"}),
+		inFlight: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace:   ns,
+			Subsystem:   "wallet_rpc",
+			Name:        "calls_in_flight",
+			Help:        "Number of monero-wallet-rpc calls waiting for an answer by method.",
+			ConstLabels: opts.ConstLabels,
+		}, []string{"method"}),
+		balance: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace:   ns,
+			Subsystem:   "wallet",
+			Name:        "balance_xmr",
+			Help:        "Balance of a wallet account as last returned by get_balance.",
+			ConstLabels: opts.ConstLabels,
+		}, []string{"account_index"}),
+		unlockedBalance: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace:   ns,
+			Subsystem:   "wallet",
+			Name:        "unlocked_balance_xmr",
+			Help:        "Unlocked balance of a wallet account as last returned by get_balance.",
+			ConstLabels: opts.ConstLabels,
+		}, []string{"account_index"}),
+		height: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace:   ns,
+			Subsystem:   "wallet",
+			Name:        "height",
+			Help:        "Wallet block height as last returned by get_height.",
+			ConstLabels: opts.ConstLabels,
+		}),
+		cacheHits: prometheus.NewDesc(
+			prometheus.BuildFQName(ns, "wallet_rpc", "cache_hits_total"),
+			"Number of monero-wallet-rpc calls answered by a wallet.Cache by method.",
+			[]string{"method"}, opts.ConstLabels),
+		cacheMisses: prometheus.NewDesc(
+			prometheus.BuildFQName(ns, "wallet_rpc", "cache_misses_total"),
+			"Number of monero-wallet-rpc calls missed by a wallet.Cache by method.",
+			[]string{"method"}, opts.ConstLabels),
+	}
+}
+
+// WatchCache exports the hits and misses of c. The stats of
+// several caches are summed up.
+func (m *Metrics) WatchCache(c *wallet.Cache) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.caches = append(m.caches, c)
+}
+
+// Describe implements prometheus.Collector.
+func (m *Metrics) Describe(ch chan<- *prometheus.Desc) {
+	m.calls.Describe(ch)
+	m.duration.Describe(ch)
+	m.inFlight.Describe(ch)
+	m.balance.Describe(ch)
+	m.unlockedBalance.Describe(ch)
+	m.height.Describe(ch)
+	ch <- m.cacheHits
+	ch <- m.cacheMisses
+}
+
+// Collect implements prometheus.Collector.
+func (m *Metrics) Collect(ch chan<- prometheus.Metric) {
+	m.calls.Collect(ch)
+	m.duration.Collect(ch)
+	m.inFlight.Collect(ch)
+	m.balance.Collect(ch)
+	m.unlockedBalance.Collect(ch)
+	m.height.Collect(ch)
+	m.collectCaches(ch)
+}
+
+func (m *Metrics) collectCaches(ch chan<- prometheus.Metric) {
+	m.mu.Lock()
+	caches := m.caches
+	m.mu.Unlock()
+	stats := make(map[string]wallet.CacheStats)
+	for _, c := range caches {
+		for method, s := range c.Stats() {
+			sum := stats[method]
+			sum.Hits += s.Hits
+			sum.Misses += s.Misses
+			stats[method] = sum
+		}
+	}
+	for method, s := range stats {
+		ch <- prometheus.MustNewConstMetric(m.cacheHits, prometheus.CounterValue, float64(s.Hits), method)
+		ch <- prometheus.MustNewConstMetric(m.cacheMisses, prometheus.CounterValue, float64(s.Misses), method)
+	}
+}
+
+// Interceptor returns a wallet.Interceptor recording the calls of a client.
+// Successful get_balance and get_height calls also update the balance and height gauges.
+func (m *Metrics) Interceptor() wallet.Interceptor {
+	return func(ctx context.Context, method string, params, result interface{}, next wallet.Invoker) error {
+		inFlight := m.inFlight.WithLabelValues(method)
+		inFlight.Inc()
+		start := time.Now()
+		err := next(ctx, method, params, result)
+		m.duration.WithLabelValues(method).Observe(time.Since(start).Seconds())
+		inFlight.Dec()
+
+		code, status := errorLabels(err)
+		m.calls.WithLabelValues(method, code, status).Inc()
+		if err == nil {
+			m.observe(params, result)
+		}
+		return err
+	}
+}
+
+// errorLabels returns the wallet error code and HTTP status labels of a call's error.
+func errorLabels(err error) (code, status string) {
+	if err == nil {
+		return "0", strconv.Itoa(http.StatusOK)
+	}
+	if isWalletError, werr := wallet.GetWalletError(err); isWalletError {
+		return strconv.Itoa(int(werr.Code)), strconv.Itoa(http.StatusOK)
+	}
+	var herr *wallet.HTTPError
+	if errors.As(err, &herr) {
+		return "", strconv.Itoa(herr.StatusCode)
+	}
+	// the call failed before a response was received
+	return "", ""
+}
+
+// observe updates the wallet gauges from the result of a successful call.
+func (m *Metrics) observe(params, result interface{}) {
+	switch res := result.(type) {
+	case *wallet.ResponseGetBalance:
+		var account uint64
+		if req, ok := params.(*wallet.RequestGetBalance); ok && req != nil {
+			account = req.AccountIndex
+		}
+		label := strconv.FormatUint(account, 10)
+		m.balance.WithLabelValues(label).Set(wallet.XMRToFloat64(res.Balance))
+		m.unlockedBalance.WithLabelValues(label).Set(wallet.XMRToFloat64(res.UnlockedBalance))
+	case *wallet.ResponseGetHeight:
+		m.height.Set(float64(res.Height))
+	}
+}
+
+// Poll calls GetHeight and GetBalance for the given accounts every interval,
+// feeding the wallet gauges, until ctx is done. The client has to be set up with
+// the Interceptor of m. Errors of single calls are counted and otherwise ignored.
+func (m *Metrics) Poll(ctx context.Context, client wallet.ContextClient, interval time.Duration, accounts ...uint64) {
+	if len(accounts) == 0 {
+		accounts = []uint64{0}
+	}
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		client.GetHeightContext(ctx)
+		for _, account := range accounts {
+			client.GetBalanceContext(ctx, &wallet.RequestGetBalance{AccountIndex: account})
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+	}
+}
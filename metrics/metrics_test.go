@@ -0,0 +1,98 @@
+package metrics
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/omani/go-monero-rpc-client/wallet"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInterceptor(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID     uint64 `json:"id"`
+			Method string `json:"method"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		resp := wallet.H{"jsonrpc": "2.0", "id": req.ID}
+		switch req.Method {
+		case "get_balance":
+			resp["result"] = wallet.H{"balance": 2500000000000, "unlocked_balance": 1500000000000}
+		case "get_height":
+			resp["result"] = wallet.H{"height": 2000}
+		case "stop_wallet":
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		default:
+			resp["error"] = wallet.H{"code": wallet.ErrWrongTxID, "message": "invalid txid"}
+		}
+		json.NewEncoder(w).Encode(resp)
+	}))
+	defer srv.Close()
+
+	m := New(Opts{})
+	cl := wallet.New(wallet.Config{
+		Address:      srv.URL,
+		Interceptors: []wallet.Interceptor{m.Interceptor()},
+	})
+	cl.GetBalance(&wallet.RequestGetBalance{AccountIndex: 1})
+	cl.GetHeight()
+	cl.GetTransferByTxID(&wallet.RequestGetTransferByTxID{TxID: "x"})
+	cl.StopWallet()
+
+	assert.NoError(t, testutil.CollectAndCompare(m, strings.NewReader(`
+# HELP monero_wallet_rpc_calls_total Number of monero-wallet-rpc calls by method, wallet error code and HTTP status.
+# TYPE monero_wallet_rpc_calls_total counter
+monero_wallet_rpc_calls_total{error_code="-8",http_status="200",method="get_transfer_by_txid"} 1
+monero_wallet_rpc_calls_total{error_code="0",http_status="200",method="get_balance"} 1
+monero_wallet_rpc_calls_total{error_code="0",http_status="200",method="get_height"} 1
+monero_wallet_rpc_calls_total{error_code="",http_status="401",method="stop_wallet"} 1
+# HELP monero_wallet_balance_xmr Balance of a wallet account as last returned by get_balance.
+# TYPE monero_wallet_balance_xmr gauge
+monero_wallet_balance_xmr{account_index="1"} 2.5
+# HELP monero_wallet_unlocked_balance_xmr Unlocked balance of a wallet account as last returned by get_balance.
+# TYPE monero_wallet_unlocked_balance_xmr gauge
+monero_wallet_unlocked_balance_xmr{account_index="1"} 1.5
+# HELP monero_wallet_height Wallet block height as last returned by get_height.
+# TYPE monero_wallet_height gauge
+monero_wallet_height 2000
+`), "monero_wallet_rpc_calls_total", "monero_wallet_balance_xmr", "monero_wallet_unlocked_balance_xmr", "monero_wallet_height"))
+
+	assert.Equal(t, 4, testutil.CollectAndCount(m, "monero_wallet_rpc_call_duration_seconds"))
+	assert.Equal(t, float64(0), testutil.ToFloat64(m.inFlight.WithLabelValues("get_height")))
+}
+
+func TestWatchCache(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(wallet.H{"jsonrpc": "2.0", "id": 1, "result": wallet.H{"address": "4abc"}})
+	}))
+	defer srv.Close()
+
+	m := New(Opts{})
+	cache := wallet.NewCache(wallet.CacheConfig{})
+	m.WatchCache(cache)
+	cl := wallet.New(wallet.Config{
+		Address:      srv.URL,
+		Interceptors: []wallet.Interceptor{cache.Interceptor(), m.Interceptor()},
+	})
+	cl.GetAddress(&wallet.RequestGetAddress{})
+	cl.GetAddress(&wallet.RequestGetAddress{})
+	cl.GetAddress(&wallet.RequestGetAddress{})
+
+	assert.NoError(t, testutil.CollectAndCompare(m, strings.NewReader(`
+# HELP monero_wallet_rpc_cache_hits_total Number of monero-wallet-rpc calls answered by a wallet.Cache by method.
+# TYPE monero_wallet_rpc_cache_hits_total counter
+monero_wallet_rpc_cache_hits_total{method="get_address"} 2
+# HELP monero_wallet_rpc_cache_misses_total Number of monero-wallet-rpc calls missed by a wallet.Cache by method.
+# TYPE monero_wallet_rpc_cache_misses_total counter
+monero_wallet_rpc_cache_misses_total{method="get_address"} 1
+# HELP monero_wallet_rpc_calls_total Number of monero-wallet-rpc calls by method, wallet error code and HTTP status.
+# TYPE monero_wallet_rpc_calls_total counter
+monero_wallet_rpc_calls_total{error_code="0",http_status="200",method="get_address"} 1
+`), "monero_wallet_rpc_cache_hits_total", "monero_wallet_rpc_cache_misses_total", "monero_wallet_rpc_calls_total"))
+}
@@ -0,0 +1,292 @@
+// Package spend makes monero-wallet-rpc spending calls idempotent.
+//
+// Every spend is keyed by an ID chosen by the caller, eg. the ID of a payout.
+// The intent is recorded in a Store before the call is sent and the tx hashes
+// once it returns. The transactions are tagged with a tx note naming the ID.
+// When a spend is attempted again with the same ID, it is not sent again:
+//
+//	spender := spend.New(client, store)
+//	rec, err := spender.Transfer(ctx, "payout-1234", req)
+//
+// If the outcome of an earlier attempt is unknown, eg. after a timeout or a
+// crash, the outgoing and pending transfers of the wallet are searched for it,
+// first by tx note and then by destinations. monero-wallet-rpc handles one
+// request at a time, so an earlier transfer still running is done by the time
+// the search is answered. A spend is only sent again if it is not found.
+package spend
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/omani/go-monero-rpc-client/wallet"
+)
+
+// NotePrefix starts the tx notes tagging transactions with the ID of their spend.
+const NotePrefix = "spend-id:"
+
+// lookupMargin is how many blocks below the height a spend was recorded at
+// are searched for its transactions. Record.Height is the block count, so the
+// next block already has that index, and a reorg may mine them lower still.
+const lookupMargin = 10
+
+// State is the state of a spend.
+type State string
+
+const (
+	// StatePending spends were recorded, but their outcome is unknown.
+	StatePending State = "pending"
+	// StateSent spends created the transactions of Record.TxHashes.
+	StateSent State = "sent"
+	// StateFailed spends were refused by monero-wallet-rpc, nothing was sent.
+	StateFailed State = "failed"
+)
+
+// Record is the persisted state of a spend.
+type Record struct {
+	ID     string `json:"id"`
+	Method string `json:"method"`
+	State  State  `json:"state"`
+	// TxHashes are the transactions of a sent spend.
+	TxHashes []string `json:"tx_hashes,omitempty"`
+	// AccountIndex, Destinations and Height are used to search
+	// the transactions of a pending spend.
+	AccountIndex uint64                `json:"account_index"`
+	Destinations []*wallet.Destination `json:"destinations"`
+	Height       uint64                `json:"height"`
+	// Err is the error of a failed spend.
+	Err     string    `json:"error,omitempty"`
+	Created time.Time `json:"created"`
+	Updated time.Time `json:"updated"`
+	// Recovered is set when the spend was sent by an earlier attempt.
+	Recovered bool `json:"-"`
+}
+
+func (r *Record) clone() *Record {
+	c := *r
+	c.TxHashes = append([]string(nil), r.TxHashes...)
+	c.Destinations = append([]*wallet.Destination(nil), r.Destinations...)
+	return &c
+}
+
+// Spender sends idempotent spends. Spends are sent one at a time.
+type Spender struct {
+	client wallet.ContextClient
+	store  Store
+	mu     sync.Mutex
+}
+
+// New returns a Spender sending through client and recording spends in store.
+// With a MultiClient, every call goes to its primary endpoint: pending
+// transfers only exist on the monero-wallet-rpc which sent them.
+func New(client wallet.ContextClient, store Store) *Spender {
+	if m, ok := client.(*wallet.MultiClient); ok {
+		client = m.Primary()
+	}
+	return &Spender{
+		client: client,
+		store:  store,
+	}
+}
+
+// Transfer sends req once for id.
+func (s *Spender) Transfer(ctx context.Context, id string, req *wallet.RequestTransfer) (*Record, error) {
+	return s.spend(ctx, id, "transfer", req.AccountIndex, req.Destinations, func(ctx context.Context) ([]string, error) {
+		resp, err := s.client.TransferContext(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		return []string{resp.TxHash}, nil
+	})
+}
+
+// TransferSplit sends req once for id.
+func (s *Spender) TransferSplit(ctx context.Context, id string, req *wallet.RequestTransferSplit) (*Record, error) {
+	return s.spend(ctx, id, "transfer_split", req.AccountIndex, req.Destinations, func(ctx context.Context) ([]string, error) {
+		resp, err := s.client.TransferSplitContext(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		return resp.TxHashList, nil
+	})
+}
+
+// SweepAll sends req once for id.
+func (s *Spender) SweepAll(ctx context.Context, id string, req *wallet.RequestSweepAll) (*Record, error) {
+	dests := []*wallet.Destination{{Address: req.Address}}
+	return s.spend(ctx, id, "sweep_all", req.AccountIndex, dests, func(ctx context.Context) ([]string, error) {
+		resp, err := s.client.SweepAllContext(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		return resp.TxHashList, nil
+	})
+}
+
+// SweepSingle sends req once for id.
+func (s *Spender) SweepSingle(ctx context.Context, id string, req *wallet.RequestSweepSingle) (*Record, error) {
+	dests := []*wallet.Destination{{Address: req.Address}}
+	return s.spend(ctx, id, "sweep_single", req.AccountIndex, dests, func(ctx context.Context) ([]string, error) {
+		resp, err := s.client.SweepSingleContext(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		return resp.TxHashList, nil
+	})
+}
+
+func (s *Spender) spend(ctx context.Context, id, method string, account uint64, dests []*wallet.Destination, send func(context.Context) ([]string, error)) (*Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rec, err := s.store.Get(ctx, id)
+	switch {
+	case errors.Is(err, ErrNotFound):
+	case err != nil:
+		return nil, err
+	case rec.Method != method:
+		return nil, fmt.Errorf("spend id %v already used for %v", id, rec.Method)
+	case rec.State == StateSent:
+		rec.Recovered = true
+		return rec, nil
+	case rec.State == StatePending:
+		hashes, err := s.lookup(ctx, rec)
+		if err != nil {
+			return nil, fmt.Errorf("looking up earlier attempt of %v: %w", id, err)
+		}
+		if len(hashes) > 0 {
+			s.tag(ctx, id, hashes)
+			rec.Recovered = true
+			return rec, s.finish(ctx, rec, hashes, nil)
+		}
+	}
+
+	height, err := s.client.GetHeightContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	rec = &Record{
+		ID:           id,
+		Method:       method,
+		State:        StatePending,
+		AccountIndex: account,
+		Destinations: dests,
+		Height:       height.Height,
+		Created:      now,
+		Updated:      now,
+	}
+	if err := s.store.Put(ctx, rec); err != nil {
+		return nil, err
+	}
+
+	hashes, err := send(ctx)
+	if err != nil {
+		if isWalletError, _ := wallet.GetWalletError(err); isWalletError {
+			// refused by the wallet, nothing was sent
+			if serr := s.finish(ctx, rec, nil, err); serr != nil {
+				return nil, errors.Join(err, serr)
+			}
+		}
+		// otherwise the outcome is unknown and the record stays pending
+		return nil, err
+	}
+	s.tag(ctx, id, hashes)
+	return rec, s.finish(ctx, rec, hashes, nil)
+}
+
+// finish records the outcome of a spend.
+func (s *Spender) finish(ctx context.Context, rec *Record, hashes []string, err error) error {
+	rec.Updated = time.Now()
+	if err != nil {
+		rec.State = StateFailed
+		rec.Err = err.Error()
+	} else {
+		rec.State = StateSent
+		rec.TxHashes = hashes
+		rec.Err = ""
+	}
+	// the spend is done, whatever happens to ctx
+	return s.store.Put(context.WithoutCancel(ctx), rec)
+}
+
+// tag sets the tx notes naming the spend. Failing to do so is not an error,
+// the transactions are still found by destinations.
+func (s *Spender) tag(ctx context.Context, id string, hashes []string) {
+	notes := make([]string, len(hashes))
+	for i := range notes {
+		notes[i] = NotePrefix + id
+	}
+	s.client.SetTxNotesContext(context.WithoutCancel(ctx), &wallet.RequestSetTxNotes{TxIDs: hashes, Notes: notes})
+}
+
+// lookup searches the transactions of a pending spend, by tx note first and
+// then by destinations among the untagged transactions sent since lookupMargin blocks
+// before it was recorded.
+func (s *Spender) lookup(ctx context.Context, rec *Record) ([]string, error) {
+	// min_height is exclusive
+	var minHeight uint64
+	if rec.Height > lookupMargin {
+		minHeight = rec.Height - lookupMargin
+	}
+	var byNote, byDest []string
+	err := s.client.StreamTransfersContext(ctx, &wallet.RequestGetTransfers{
+		Out:            true,
+		Pending:        true,
+		FilterByHeight: true,
+		MinHeight:      minHeight,
+		AccountIndex:   rec.AccountIndex,
+	}, func(t *wallet.Transfer) error {
+		switch {
+		case t.Note == NotePrefix+rec.ID:
+			byNote = append(byNote, t.TxID)
+		case t.Note == "" && matches(rec, t):
+			byDest = append(byDest, t.TxID)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(byNote) > 0 {
+		return byNote, nil
+	}
+	return byDest, nil
+}
+
+// matches tells whether t may be a transaction of rec: all of its destinations
+// are destinations of rec, with the same amounts for a plain transfer.
+func matches(rec *Record, t *wallet.Transfer) bool {
+	if len(t.Destinations) == 0 {
+		return false
+	}
+	if rec.Method == "transfer" {
+		if len(t.Destinations) != len(rec.Destinations) {
+			return false
+		}
+		left := make(map[wallet.Destination]int)
+		for _, d := range rec.Destinations {
+			left[*d]++
+		}
+		for _, d := range t.Destinations {
+			if left[*d] == 0 {
+				return false
+			}
+			left[*d]--
+		}
+		return true
+	}
+	addresses := make(map[string]bool)
+	for _, d := range rec.Destinations {
+		addresses[d.Address] = true
+	}
+	for _, d := range t.Destinations {
+		if !addresses[d.Address] {
+			return false
+		}
+	}
+	return true
+}
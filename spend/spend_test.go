@@ -0,0 +1,254 @@
+package spend
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/omani/go-monero-rpc-client/wallet"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeWallet is a monero-wallet-rpc keeping the transfers it sent.
+type fakeWallet struct {
+	mu        sync.Mutex
+	transfers []*wallet.Transfer
+	calls     map[string]int
+	// loseResponse makes transfer fail with 503 after sending.
+	loseResponse bool
+	// refuse makes transfer fail with not enough money.
+	refuse bool
+	// height is the wallet height.
+	height uint64
+}
+
+func newFakeWallet(t *testing.T) (*fakeWallet, wallet.ContextClient) {
+	w, srv := startFakeWallet(t)
+	return w, wallet.NewContextClient(wallet.Config{Address: srv.URL})
+}
+
+func startFakeWallet(t *testing.T) (*fakeWallet, *httptest.Server) {
+	w := &fakeWallet{calls: make(map[string]int), height: 100}
+	srv := httptest.NewServer(http.HandlerFunc(w.serve))
+	t.Cleanup(srv.Close)
+	return w, srv
+}
+
+func (w *fakeWallet) serve(rw http.ResponseWriter, r *http.Request) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	var req struct {
+		ID     json.RawMessage `json:"id"`
+		Method string          `json:"method"`
+		Params json.RawMessage `json:"params"`
+	}
+	json.NewDecoder(r.Body).Decode(&req)
+	w.calls[req.Method]++
+	resp := wallet.H{"jsonrpc": "2.0", "id": req.ID}
+
+	switch req.Method {
+	case "get_version":
+		resp["result"] = wallet.H{"version": 65562}
+	case "get_height":
+		resp["result"] = wallet.H{"height": w.height}
+	case "transfer":
+		if w.refuse {
+			resp["error"] = wallet.H{"code": wallet.ErrNotEnoughMoney, "message": "not enough money"}
+			break
+		}
+		params := &wallet.RequestTransfer{}
+		json.Unmarshal(req.Params, params)
+		hash := fmt.Sprintf("tx%d", len(w.transfers)+1)
+		w.transfers = append(w.transfers, &wallet.Transfer{TxID: hash, Destinations: params.Destinations, Type: "pending"})
+		if w.loseResponse {
+			rw.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		resp["result"] = wallet.H{"tx_hash": hash}
+	case "set_tx_notes":
+		params := &wallet.RequestSetTxNotes{}
+		json.Unmarshal(req.Params, params)
+		for i, txid := range params.TxIDs {
+			for _, t := range w.transfers {
+				if t.TxID == txid {
+					t.Note = params.Notes[i]
+				}
+			}
+		}
+		resp["result"] = wallet.H{}
+	case "get_transfers":
+		// like wallet2, min_height excludes its own block
+		params := &wallet.RequestGetTransfers{}
+		json.Unmarshal(req.Params, params)
+		var out, pending []*wallet.Transfer
+		for _, t := range w.transfers {
+			switch {
+			case t.Type == "pending":
+				pending = append(pending, t)
+			case !params.FilterByHeight || t.Height > params.MinHeight:
+				out = append(out, t)
+			}
+		}
+		// empty lists are left out, like monero-wallet-rpc does
+		result := wallet.H{}
+		if len(out) > 0 {
+			result["out"] = out
+		}
+		if len(pending) > 0 {
+			result["pending"] = pending
+		}
+		resp["result"] = result
+	}
+	json.NewEncoder(rw).Encode(resp)
+}
+
+// confirm mines the pending transfers in the block at height.
+func (w *fakeWallet) confirm(height uint64) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for _, t := range w.transfers {
+		if t.Type == "pending" {
+			t.Type = "out"
+			t.Height = height
+		}
+	}
+}
+
+func payout(amount uint64) *wallet.RequestTransfer {
+	return &wallet.RequestTransfer{Destinations: []*wallet.Destination{{Address: "4abc", Amount: amount}}}
+}
+
+func TestTransferOnce(t *testing.T) {
+	w, cl := newFakeWallet(t)
+	s := New(cl, NewMemoryStore())
+
+	rec, err := s.Transfer(context.Background(), "payout-1", payout(5))
+	assert.NoError(t, err)
+	assert.Equal(t, StateSent, rec.State)
+	assert.Equal(t, []string{"tx1"}, rec.TxHashes)
+	assert.False(t, rec.Recovered)
+	assert.Equal(t, NotePrefix+"payout-1", w.transfers[0].Note)
+
+	rec, err = s.Transfer(context.Background(), "payout-1", payout(5))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"tx1"}, rec.TxHashes)
+	assert.True(t, rec.Recovered)
+	assert.Equal(t, 1, w.calls["transfer"])
+
+	_, err = s.SweepAll(context.Background(), "payout-1", &wallet.RequestSweepAll{Address: "4abc"})
+	assert.Error(t, err)
+}
+
+func TestTransferLostResponse(t *testing.T) {
+	w, cl := newFakeWallet(t)
+	store, err := NewFileStore(t.TempDir())
+	assert.NoError(t, err)
+
+	// an unrelated transfer to the same address, which must not be mistaken for the payout
+	_, err = New(cl, store).Transfer(context.Background(), "payout-0", payout(7))
+	assert.NoError(t, err)
+
+	w.loseResponse = true
+	_, err = New(cl, store).Transfer(context.Background(), "payout-1", payout(5))
+	var herr *wallet.HTTPError
+	assert.True(t, errors.As(err, &herr))
+	rec, err := store.Get(context.Background(), "payout-1")
+	assert.NoError(t, err)
+	assert.Equal(t, StatePending, rec.State)
+	assert.Equal(t, uint64(100), rec.Height)
+
+	// a new process retries the payout
+	w.loseResponse = false
+	rec, err = New(cl, store).Transfer(context.Background(), "payout-1", payout(5))
+	assert.NoError(t, err)
+	assert.True(t, rec.Recovered)
+	assert.Equal(t, []string{"tx2"}, rec.TxHashes)
+	assert.Equal(t, 2, w.calls["transfer"])
+	assert.Equal(t, NotePrefix+"payout-1", w.transfers[1].Note)
+
+	rec, err = store.Get(context.Background(), "payout-1")
+	assert.NoError(t, err)
+	assert.Equal(t, StateSent, rec.State)
+}
+
+func TestTransferLostResponseConfirmed(t *testing.T) {
+	w, cl := newFakeWallet(t)
+	store := NewMemoryStore()
+
+	w.loseResponse = true
+	_, err := New(cl, store).Transfer(context.Background(), "payout-1", payout(5))
+	assert.Error(t, err)
+	// get_height returned the block count 100, the next block has index 100
+	w.confirm(100)
+
+	w.loseResponse = false
+	rec, err := New(cl, store).Transfer(context.Background(), "payout-1", payout(5))
+	assert.NoError(t, err)
+	assert.True(t, rec.Recovered)
+	assert.Equal(t, []string{"tx1"}, rec.TxHashes)
+	assert.Equal(t, 1, w.calls["transfer"])
+}
+
+func TestTransferLostResponseMulti(t *testing.T) {
+	primary, psrv := startFakeWallet(t)
+	secondary, ssrv := startFakeWallet(t)
+	// the secondary is ahead, so read-only calls prefer it
+	secondary.height = 101
+	cl, err := wallet.NewMulti(wallet.MultiConfig{
+		Endpoints: []wallet.Config{{Address: psrv.URL}, {Address: ssrv.URL}},
+	})
+	assert.NoError(t, err)
+	cl.CheckHealth(context.Background())
+	store := NewMemoryStore()
+
+	primary.loseResponse = true
+	_, err = New(cl, store).Transfer(context.Background(), "payout-1", payout(5))
+	assert.Error(t, err)
+
+	// the pending transfer is only known to the primary
+	primary.loseResponse = false
+	rec, err := New(cl, store).Transfer(context.Background(), "payout-1", payout(5))
+	assert.NoError(t, err)
+	assert.True(t, rec.Recovered)
+	assert.Equal(t, []string{"tx1"}, rec.TxHashes)
+	assert.Equal(t, 1, primary.calls["transfer"])
+	assert.Zero(t, secondary.calls["get_transfers"])
+}
+
+func TestTransferRefused(t *testing.T) {
+	w, cl := newFakeWallet(t)
+	store := NewMemoryStore()
+	s := New(cl, store)
+
+	w.refuse = true
+	_, err := s.Transfer(context.Background(), "payout-1", payout(5))
+	assert.True(t, errors.Is(err, wallet.ErrNotEnoughMoney))
+	rec, err := store.Get(context.Background(), "payout-1")
+	assert.NoError(t, err)
+	assert.Equal(t, StateFailed, rec.State)
+
+	w.refuse = false
+	rec, err = s.Transfer(context.Background(), "payout-1", payout(5))
+	assert.NoError(t, err)
+	assert.False(t, rec.Recovered)
+	assert.Equal(t, []string{"tx1"}, rec.TxHashes)
+}
+
+func TestFileStore(t *testing.T) {
+	store, err := NewFileStore(t.TempDir())
+	assert.NoError(t, err)
+
+	_, err = store.Get(context.Background(), "a/b")
+	assert.Equal(t, ErrNotFound, err)
+
+	rec := &Record{ID: "a/b", Method: "transfer", State: StateSent, TxHashes: []string{"x"}}
+	assert.NoError(t, store.Put(context.Background(), rec))
+	got, err := store.Get(context.Background(), "a/b")
+	assert.NoError(t, err)
+	assert.Equal(t, rec, got)
+}
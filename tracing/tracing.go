@@ -0,0 +1,142 @@
+// Package tracing creates OpenTelemetry spans for monero-wallet-rpc calls.
+//
+// Spans are created by an interceptor and named after the JSON-RPC method,
+// eg. "monero.wallet.transfer". They are children of the span carried by the
+// context passed to the Context methods of a wallet.ContextClient:
+//
+//	client := wallet.NewContextClient(wallet.Config{
+//		Address:      "http://127.0.0.1:6061/json_rpc",
+//		Interceptors: []wallet.Interceptor{tracing.Interceptor()},
+//	})
+//
+// Only a fixed set of attributes is recorded (account index, subaddress count,
+// wallet error code and transaction hashes). Request and response payloads,
+// which may carry passwords, keys or seeds, never end up in a span.
+package tracing
+
+import (
+	"context"
+	"errors"
+	"reflect"
+
+	"github.com/omani/go-monero-rpc-client/wallet"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/omani/go-monero-rpc-client/tracing"
+
+// Attribute keys set on spans.
+const (
+	MethodKey          = attribute.Key("rpc.method")
+	AccountIndexKey    = attribute.Key("monero.wallet.account_index")
+	SubaddressCountKey = attribute.Key("monero.wallet.subaddress_count")
+	ErrorCodeKey       = attribute.Key("monero.wallet.error_code")
+	HTTPStatusKey      = attribute.Key("http.response.status_code")
+	TxHashKey          = attribute.Key("monero.tx_hash")
+)
+
+// Option configures the interceptor.
+type Option func(*config)
+
+type config struct {
+	provider trace.TracerProvider
+}
+
+// WithTracerProvider sets the TracerProvider spans are created with.
+// Defaults to the global provider.
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return func(c *config) {
+		c.provider = tp
+	}
+}
+
+// Interceptor returns a wallet.Interceptor creating a span for every call.
+func Interceptor(opts ...Option) wallet.Interceptor {
+	cfg := &config{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	if cfg.provider == nil {
+		cfg.provider = otel.GetTracerProvider()
+	}
+	tracer := cfg.provider.Tracer(instrumentationName)
+
+	return func(ctx context.Context, method string, params, result interface{}, next wallet.Invoker) error {
+		ctx, span := tracer.Start(ctx, "monero.wallet."+method,
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(MethodKey.String(method)),
+		)
+		defer span.End()
+		span.SetAttributes(requestAttributes(params)...)
+
+		err := next(ctx, method, params, result)
+		if err != nil {
+			span.SetAttributes(errorAttributes(err)...)
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+			return err
+		}
+		span.SetAttributes(responseAttributes(result)...)
+		return nil
+	}
+}
+
+// requestAttributes picks the account index and the number of requested subaddresses from params.
+func requestAttributes(params interface{}) []attribute.KeyValue {
+	v, ok := structValue(params)
+	if !ok {
+		return nil
+	}
+	var attrs []attribute.KeyValue
+	if f := v.FieldByName("AccountIndex"); f.IsValid() && f.Kind() == reflect.Uint64 {
+		attrs = append(attrs, AccountIndexKey.Int64(int64(f.Uint())))
+	}
+	for _, name := range []string{"AddressIndices", "AddressIndex", "SubaddrIndices"} {
+		if f := v.FieldByName(name); f.IsValid() && f.Kind() == reflect.Slice {
+			attrs = append(attrs, SubaddressCountKey.Int(f.Len()))
+		}
+	}
+	return attrs
+}
+
+// responseAttributes picks the transaction hashes from result.
+func responseAttributes(result interface{}) []attribute.KeyValue {
+	v, ok := structValue(result)
+	if !ok {
+		return nil
+	}
+	if f := v.FieldByName("TxHash"); f.IsValid() && f.Kind() == reflect.String && f.String() != "" {
+		return []attribute.KeyValue{TxHashKey.String(f.String())}
+	}
+	if f := v.FieldByName("TxHashList"); f.IsValid() && f.Kind() == reflect.Slice && f.Len() > 0 {
+		if hashes, ok := f.Interface().([]string); ok {
+			return []attribute.KeyValue{TxHashKey.StringSlice(hashes)}
+		}
+	}
+	return nil
+}
+
+func errorAttributes(err error) []attribute.KeyValue {
+	if isWalletError, werr := wallet.GetWalletError(err); isWalletError {
+		return []attribute.KeyValue{ErrorCodeKey.Int(int(werr.Code))}
+	}
+	var herr *wallet.HTTPError
+	if errors.As(err, &herr) {
+		return []attribute.KeyValue{HTTPStatusKey.Int(herr.StatusCode)}
+	}
+	return nil
+}
+
+func structValue(i interface{}) (reflect.Value, bool) {
+	v := reflect.ValueOf(i)
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return reflect.Value{}, false
+		}
+		v = v.Elem()
+	}
+	return v, v.Kind() == reflect.Struct
+}
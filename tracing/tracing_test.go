@@ -0,0 +1,73 @@
+package tracing
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/omani/go-monero-rpc-client/wallet"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestInterceptor(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID     uint64 `json:"id"`
+			Method string `json:"method"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		resp := wallet.H{"jsonrpc": "2.0", "id": req.ID}
+		switch req.Method {
+		case "transfer":
+			resp["result"] = wallet.H{"tx_hash": "deadbeef", "tx_key": "secret"}
+		default:
+			resp["error"] = wallet.H{"code": wallet.ErrNotOpen, "message": "No wallet file"}
+		}
+		json.NewEncoder(w).Encode(resp)
+	}))
+	defer srv.Close()
+
+	rec := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(rec))
+	cl := wallet.NewContextClient(wallet.Config{
+		Address:      srv.URL,
+		Interceptors: []wallet.Interceptor{Interceptor(WithTracerProvider(tp))},
+	})
+
+	ctx, parent := tp.Tracer("test").Start(context.Background(), "payout")
+	_, err := cl.TransferContext(ctx, &wallet.RequestTransfer{
+		AccountIndex:   2,
+		SubaddrIndices: []uint64{1, 2, 3},
+	})
+	assert.NoError(t, err)
+	err = cl.OpenWalletContext(ctx, &wallet.RequestOpenWallet{Filename: "w", Password: "hunter2"})
+	assert.Error(t, err)
+	parent.End()
+
+	spans := rec.Ended()
+	assert.Len(t, spans, 3)
+
+	transfer := spans[0]
+	assert.Equal(t, "monero.wallet.transfer", transfer.Name())
+	assert.Equal(t, parent.SpanContext().SpanID(), transfer.Parent().SpanID())
+	assert.ElementsMatch(t, []attribute.KeyValue{
+		MethodKey.String("transfer"),
+		AccountIndexKey.Int64(2),
+		SubaddressCountKey.Int(3),
+		TxHashKey.String("deadbeef"),
+	}, transfer.Attributes())
+
+	open := spans[1]
+	assert.Equal(t, "monero.wallet.open_wallet", open.Name())
+	assert.Equal(t, codes.Error, open.Status().Code)
+	assert.ElementsMatch(t, []attribute.KeyValue{
+		MethodKey.String("open_wallet"),
+		ErrorCodeKey.Int(int(wallet.ErrNotOpen)),
+	}, open.Attributes())
+}
@@ -0,0 +1,207 @@
+package wallet
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"sync/atomic"
+)
+
+// BatchElem is a single call of a batch request.
+type BatchElem struct {
+	// JSON-RPC method name, eg. "get_transfer_by_txid".
+	Method string
+	// Request struct of the method, eg. *RequestGetTransferByTxID. May be nil.
+	Params interface{}
+	// Pointer to the response struct of the method the result is decoded into,
+	// eg. *ResponseGetTransferByTxID. May be nil.
+	Result interface{}
+	// Error is set after the batch is sent if this call failed.
+	// Use GetWalletError to check for monero-wallet-rpc errors.
+	Error error
+}
+
+// errBatchUnsupported is returned by sendBatch if the server does not accept JSON-RPC batches.
+var errBatchUnsupported = errors.New("json-rpc batch requests are not supported by the server")
+
+func (c *client) BatchCallContext(ctx context.Context, elems []*BatchElem) error {
+	if len(elems) == 0 {
+		return nil
+	}
+	if !c.inSession {
+		if err := c.session.acquire(ctx); err != nil {
+			return err
+		}
+		defer c.session.release()
+	}
+	if c.intercept == nil {
+		return c.batch(ctx, elems)
+	}
+
+	// Every element goes through the interceptors on its own. The calls reaching
+	// the end of the chain are gathered and sent as one batch.
+	g := &batchGather{
+		c:       c,
+		ctx:     ctx,
+		active:  len(elems),
+		flushed: make(chan struct{}),
+	}
+	var wg sync.WaitGroup
+	for _, e := range elems {
+		wg.Add(1)
+		go func(e *BatchElem) {
+			defer wg.Done()
+			e.Error = c.intercept(ctx, e.Method, e.Params, e.Result, g.invoke)
+			g.leave()
+		}(e)
+	}
+	wg.Wait()
+	return g.err
+}
+
+// batch sends elems bypassing the interceptors.
+func (c *client) batch(ctx context.Context, elems []*BatchElem) error {
+	if atomic.LoadInt32(&c.noBatch) == 0 {
+		err := c.sendBatch(ctx, elems)
+		if err != errBatchUnsupported {
+			return err
+		}
+		atomic.StoreInt32(&c.noBatch, 1)
+	}
+
+	// fall back to one call after another
+	for _, e := range elems {
+		e.Error = c.invoke(ctx, e.Method, e.Params, e.Result)
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// batchGather collects the calls of a batch as they come out of the interceptor chain.
+type batchGather struct {
+	c   *client
+	ctx context.Context
+
+	mu sync.Mutex
+	// number of elements still running through the interceptors
+	active  int
+	parked  []*BatchElem
+	flushed chan struct{}
+	err     error
+}
+
+// invoke is the end of the interceptor chain for batch elements. It waits until all
+// elements have either arrived here or returned, then sends the batch. Calls made
+// after the batch was sent (eg. by a retrying interceptor) are sent on their own.
+func (g *batchGather) invoke(ctx context.Context, method string, params, result interface{}) error {
+	e := &BatchElem{
+		Method: method,
+		Params: params,
+		Result: result,
+	}
+	g.mu.Lock()
+	select {
+	case <-g.flushed:
+		g.mu.Unlock()
+		return g.c.invoke(ctx, method, params, result)
+	default:
+	}
+	g.parked = append(g.parked, e)
+	g.active--
+	g.flushIfDone()
+	g.mu.Unlock()
+
+	<-g.flushed
+	g.mu.Lock()
+	g.active++
+	g.mu.Unlock()
+	if g.err != nil {
+		return g.err
+	}
+	return e.Error
+}
+
+// leave is called when an element returned from the interceptor chain.
+func (g *batchGather) leave() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.active--
+	g.flushIfDone()
+}
+
+// flushIfDone sends the parked calls once no element is running through the interceptors anymore.
+// g.mu must be held.
+func (g *batchGather) flushIfDone() {
+	select {
+	case <-g.flushed:
+		return
+	default:
+	}
+	if g.active > 0 {
+		return
+	}
+	if len(g.parked) > 0 {
+		g.err = g.c.batch(g.ctx, g.parked)
+	}
+	close(g.flushed)
+}
+
+// sendBatch sends elems as a single JSON-RPC array and sets the result or error of each element.
+func (c *client) sendBatch(ctx context.Context, elems []*BatchElem) error {
+	reqs := make([]*clientRequest, len(elems))
+	methods := make([]string, len(elems))
+	ids := make(map[string]*BatchElem, len(elems))
+	for i, e := range elems {
+		req, id, err := c.codec.request(e.Method, e.Params)
+		if err != nil {
+			return err
+		}
+		reqs[i] = req
+		methods[i] = e.Method
+		ids[string(id)] = e
+	}
+	if len(ids) != len(elems) {
+		return errors.New("duplicate request ids in batch")
+	}
+	payload, err := json.Marshal(reqs)
+	if err != nil {
+		return err
+	}
+
+	err = c.ep.send(ctx, methods, payload, func(body io.Reader) error {
+		var raw json.RawMessage
+		if err := json.NewDecoder(body).Decode(&raw); err != nil {
+			return err
+		}
+		// a server without batch support answers with a single error object
+		if !bytes.HasPrefix(bytes.TrimSpace(raw), []byte("[")) {
+			return errBatchUnsupported
+		}
+		var resps []*clientResponse
+		if err := json.Unmarshal(raw, &resps); err != nil {
+			return err
+		}
+
+		for _, e := range elems {
+			e.Error = fmt.Errorf("no response for %v in batch", e.Method)
+		}
+		for _, resp := range resps {
+			if e, ok := ids[string(resp.ID)]; ok {
+				e.Error = c.codec.result(resp, e.Result)
+			}
+		}
+		return nil
+	})
+	var herr *HTTPError
+	if errors.As(err, &herr) && herr.StatusCode == http.StatusBadRequest {
+		return errBatchUnsupported
+	}
+	return err
+}
@@ -0,0 +1,77 @@
+package wallet
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBatchCall(t *testing.T) {
+	var posts int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&posts, 1)
+		var reqs []rpcRequest
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&reqs))
+		resps := []H{}
+		// answer in reverse order to check matching by id
+		for i := len(reqs) - 1; i >= 0; i-- {
+			req := reqs[i]
+			switch req.Method {
+			case "get_height":
+				resps = append(resps, H{"jsonrpc": "2.0", "id": req.ID, "result": H{"height": 42}})
+			case "get_tx_notes":
+				resps = append(resps, H{"jsonrpc": "2.0", "id": req.ID, "result": H{"notes": []string{"a", "b"}}})
+			default:
+				resps = append(resps, H{"jsonrpc": "2.0", "id": req.ID, "error": H{"code": ErrWrongTxID, "message": "invalid txid"}})
+			}
+		}
+		json.NewEncoder(w).Encode(resps)
+	}))
+	defer srv.Close()
+	cl := NewContextClient(Config{Address: srv.URL})
+
+	height := &ResponseGetHeight{}
+	notes := &ResponseGetTxNotes{}
+	elems := []*BatchElem{
+		{Method: "get_height", Result: height},
+		{Method: "get_tx_notes", Params: &RequestGetTxNotes{TxIDs: []string{"a", "b"}}, Result: notes},
+		{Method: "get_transfer_by_txid", Params: &RequestGetTransferByTxID{TxID: "x"}, Result: &ResponseGetTransferByTxID{}},
+	}
+	assert.NoError(t, cl.BatchCallContext(context.Background(), elems))
+	assert.Equal(t, int32(1), posts)
+
+	assert.NoError(t, elems[0].Error)
+	assert.Equal(t, uint64(42), height.Height)
+	assert.NoError(t, elems[1].Error)
+	assert.Equal(t, []string{"a", "b"}, notes.Notes)
+	isWalletError, werr := GetWalletError(elems[2].Error)
+	assert.True(t, isWalletError)
+	assert.Equal(t, ErrWrongTxID, werr.Code)
+}
+
+func TestBatchCallFallback(t *testing.T) {
+	var calls int32
+	// newTestServer only understands single requests and answers arrays with 400 Bad Request
+	srv := newTestServer(t, func(req *rpcRequest) interface{} {
+		atomic.AddInt32(&calls, 1)
+		return H{"height": 7}
+	})
+	cl := NewContextClient(Config{Address: srv.URL})
+
+	h1, h2 := &ResponseGetHeight{}, &ResponseGetHeight{}
+	elems := []*BatchElem{
+		{Method: "get_height", Result: h1},
+		{Method: "get_height", Result: h2},
+	}
+	assert.NoError(t, cl.BatchCallContext(context.Background(), elems))
+	assert.NoError(t, elems[0].Error)
+	assert.NoError(t, elems[1].Error)
+	assert.Equal(t, uint64(7), h1.Height)
+	assert.Equal(t, uint64(7), h2.Height)
+	assert.Equal(t, int32(2), calls)
+}
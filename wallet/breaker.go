@@ -0,0 +1,222 @@
+package wallet
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned without sending the call while a Breaker is open.
+var ErrCircuitOpen = errors.New("circuit breaker open")
+
+// BreakerState is the state of a Breaker.
+type BreakerState int
+
+const (
+	// BreakerClosed lets calls through.
+	BreakerClosed BreakerState = iota
+	// BreakerOpen fails calls with ErrCircuitOpen until the cooldown is over.
+	BreakerOpen
+	// BreakerHalfOpen fails calls with ErrCircuitOpen while monero-wallet-rpc is probed.
+	BreakerHalfOpen
+)
+
+func (s BreakerState) String() string {
+	switch s {
+	case BreakerClosed:
+		return "closed"
+	case BreakerOpen:
+		return "open"
+	case BreakerHalfOpen:
+		return "half-open"
+	}
+	return "unknown"
+}
+
+// BreakerEvent describes a state change of a Breaker.
+type BreakerEvent struct {
+	From, To BreakerState
+	// Err is the error which opened the breaker, or made a probe fail.
+	Err  error
+	Time time.Time
+}
+
+// BreakerConfig configures a Breaker.
+type BreakerConfig struct {
+	// FailureThreshold is the number of consecutive daemon or transport errors
+	// opening the breaker. Defaults to 5.
+	FailureThreshold int
+	// Cooldown is how long the breaker stays open before probing. Defaults to 10s.
+	Cooldown time.Duration
+	// ProbeTimeout bounds the get_version and get_height calls of a probe. Defaults to 10s.
+	ProbeTimeout time.Duration
+	// OnStateChange is called on every state change.
+	OnStateChange func(BreakerEvent)
+}
+
+// Breaker is a circuit breaker stopping calls while monero-wallet-rpc or its
+// daemon is unreachable, instead of hammering it with calls bound to fail.
+//
+// It opens after FailureThreshold consecutive transport errors, retryable HTTP
+// statuses or daemon errors (ErrDaemonIsBusy, ErrNoDaemonConnection). Other
+// wallet errors, like ErrNotEnoughMoney, prove the server works and reset the
+// count. Calls whose context was canceled or ran out are not counted at all.
+// Once the cooldown is over, the breaker probes with get_version and
+// get_height and closes again if both succeed.
+//
+// A Breaker is installed with its Interceptor, ideally as the outermost one.
+type Breaker struct {
+	cfg BreakerConfig
+
+	mu       sync.Mutex
+	state    BreakerState
+	failures int
+	next     Invoker
+	closed   chan struct{}
+}
+
+// NewBreaker returns a closed circuit breaker.
+func NewBreaker(cfg BreakerConfig) *Breaker {
+	if cfg.FailureThreshold <= 0 {
+		cfg.FailureThreshold = 5
+	}
+	if cfg.Cooldown <= 0 {
+		cfg.Cooldown = 10 * time.Second
+	}
+	if cfg.ProbeTimeout <= 0 {
+		cfg.ProbeTimeout = 10 * time.Second
+	}
+	closed := make(chan struct{})
+	close(closed)
+	return &Breaker{
+		cfg:    cfg,
+		closed: closed,
+	}
+}
+
+// State returns the current state of the breaker.
+func (b *Breaker) State() BreakerState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state
+}
+
+// Wait blocks until the breaker is closed or ctx is done.
+func (b *Breaker) Wait(ctx context.Context) error {
+	b.mu.Lock()
+	closed := b.closed
+	b.mu.Unlock()
+	select {
+	case <-closed:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Interceptor returns the interceptor guarding calls with the breaker.
+func (b *Breaker) Interceptor() Interceptor {
+	return func(ctx context.Context, method string, params, result interface{}, next Invoker) error {
+		b.mu.Lock()
+		b.next = next
+		if b.state != BreakerClosed {
+			b.mu.Unlock()
+			return ErrCircuitOpen
+		}
+		b.mu.Unlock()
+
+		err := next(ctx, method, params, result)
+		// a call outliving the deadline of its caller says nothing
+		// about the health of monero-wallet-rpc
+		if ctx.Err() == nil {
+			b.record(err)
+		}
+		return err
+	}
+}
+
+// record counts the outcome of a call.
+func (b *Breaker) record(err error) {
+	b.mu.Lock()
+	if b.state != BreakerClosed {
+		b.mu.Unlock()
+		return
+	}
+	if !tripsBreaker(err) {
+		b.failures = 0
+		b.mu.Unlock()
+		return
+	}
+	b.failures++
+	if b.failures < b.cfg.FailureThreshold {
+		b.mu.Unlock()
+		return
+	}
+	ev := b.open(err)
+	b.mu.Unlock()
+	b.emit(ev)
+}
+
+// open opens the breaker and schedules a probe. b.mu must be held.
+func (b *Breaker) open(err error) BreakerEvent {
+	ev := b.setState(BreakerOpen, err)
+	if ev.From == BreakerClosed {
+		b.closed = make(chan struct{})
+	}
+	time.AfterFunc(b.cfg.Cooldown, b.probe)
+	return ev
+}
+
+// probe checks whether monero-wallet-rpc is back, closing the breaker if so.
+func (b *Breaker) probe() {
+	b.mu.Lock()
+	next := b.next
+	ev := b.setState(BreakerHalfOpen, nil)
+	b.mu.Unlock()
+	b.emit(ev)
+
+	ctx, cancel := context.WithTimeout(context.Background(), b.cfg.ProbeTimeout)
+	defer cancel()
+	err := next(ctx, "get_version", nil, &ResponseGetVersion{})
+	if !tripsBreaker(err) {
+		err = next(ctx, "get_height", nil, &ResponseGetHeight{})
+	}
+
+	b.mu.Lock()
+	if tripsBreaker(err) {
+		ev = b.open(err)
+	} else {
+		ev = b.setState(BreakerClosed, nil)
+		b.failures = 0
+		close(b.closed)
+	}
+	b.mu.Unlock()
+	b.emit(ev)
+}
+
+// setState changes the state, returning the event to emit. b.mu must be held.
+func (b *Breaker) setState(to BreakerState, err error) BreakerEvent {
+	ev := BreakerEvent{From: b.state, To: to, Err: err, Time: time.Now()}
+	b.state = to
+	return ev
+}
+
+func (b *Breaker) emit(ev BreakerEvent) {
+	if b.cfg.OnStateChange != nil {
+		b.cfg.OnStateChange(ev)
+	}
+}
+
+// tripsBreaker tells whether err shows monero-wallet-rpc or its daemon
+// to be unreachable, as opposed to a call being refused.
+func tripsBreaker(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+	var werr *WalletError
+	if errors.As(err, &werr) {
+		return werr.Retryable()
+	}
+	return IsRetryable(err)
+}
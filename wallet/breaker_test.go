@@ -0,0 +1,109 @@
+package wallet
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newDaemonServer answers with ErrNoDaemonConnection while down is set.
+func newDaemonServer(t *testing.T, down *int32, calls *int32) *httptest.Server {
+	return newTestServer(t, func(req *rpcRequest) interface{} {
+		atomic.AddInt32(calls, 1)
+		switch {
+		case atomic.LoadInt32(down) == 1:
+			return &WalletError{Code: ErrNoDaemonConnection, Message: "No connection to daemon"}
+		case req.Method == "transfer":
+			return &WalletError{Code: ErrNotEnoughMoney, Message: "not enough money"}
+		}
+		return H{"height": 1, "version": 65539}
+	})
+}
+
+func TestBreaker(t *testing.T) {
+	var down, calls int32 = 1, 0
+	srv := newDaemonServer(t, &down, &calls)
+
+	var (
+		mu     sync.Mutex
+		events []BreakerEvent
+	)
+	b := NewBreaker(BreakerConfig{
+		FailureThreshold: 3,
+		Cooldown:         20 * time.Millisecond,
+		OnStateChange: func(ev BreakerEvent) {
+			mu.Lock()
+			events = append(events, ev)
+			mu.Unlock()
+		},
+	})
+	cl := New(Config{Address: srv.URL, Interceptors: []Interceptor{b.Interceptor()}})
+
+	for i := 0; i < 3; i++ {
+		_, err := cl.GetBalance(&RequestGetBalance{})
+		assert.True(t, errors.Is(err, ErrNoDaemonConnection))
+	}
+	assert.Equal(t, BreakerOpen, b.State())
+	_, err := cl.GetBalance(&RequestGetBalance{})
+	assert.Equal(t, ErrCircuitOpen, err)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+
+	// the first probe fails, the second one closes the breaker
+	for n := 0; n < 3; {
+		time.Sleep(time.Millisecond)
+		mu.Lock()
+		n = len(events)
+		mu.Unlock()
+	}
+	atomic.StoreInt32(&down, 0)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	assert.NoError(t, b.Wait(ctx))
+	_, err = cl.GetBalance(&RequestGetBalance{})
+	assert.NoError(t, err)
+
+	mu.Lock()
+	defer mu.Unlock()
+	var states []BreakerState
+	for _, ev := range events {
+		states = append(states, ev.To)
+	}
+	assert.Equal(t, []BreakerState{BreakerOpen, BreakerHalfOpen, BreakerOpen, BreakerHalfOpen, BreakerClosed}, states)
+	assert.True(t, errors.Is(events[0].Err, ErrNoDaemonConnection))
+}
+
+func TestBreakerIgnoresWalletErrors(t *testing.T) {
+	var down, calls int32
+	srv := newDaemonServer(t, &down, &calls)
+	b := NewBreaker(BreakerConfig{FailureThreshold: 2})
+	cl := New(Config{Address: srv.URL, Interceptors: []Interceptor{b.Interceptor()}})
+
+	for i := 0; i < 5; i++ {
+		_, err := cl.Transfer(&RequestTransfer{})
+		assert.True(t, errors.Is(err, ErrNotEnoughMoney))
+	}
+	assert.Equal(t, BreakerClosed, b.State())
+}
+
+func TestBreakerIgnoresCallerDeadline(t *testing.T) {
+	srv := newTestServer(t, func(req *rpcRequest) interface{} {
+		time.Sleep(50 * time.Millisecond)
+		return H{}
+	})
+	b := NewBreaker(BreakerConfig{FailureThreshold: 2})
+	cl := NewContextClient(Config{Address: srv.URL, Interceptors: []Interceptor{b.Interceptor()}})
+
+	for i := 0; i < 3; i++ {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Millisecond)
+		_, err := cl.RefreshContext(ctx, &RequestRefresh{})
+		cancel()
+		assert.True(t, errors.Is(err, context.DeadlineExceeded))
+	}
+	assert.Equal(t, BreakerClosed, b.State())
+}
@@ -0,0 +1,244 @@
+package wallet
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+)
+
+// cachedMethods are the methods a Cache keeps results of. They return wallet
+// data which only changes through the calls of cacheInvalidations.
+var cachedMethods = map[string]bool{
+	"get_address":       true,
+	"get_address_index": true,
+	"get_accounts":      true,
+	"get_account_tags":  true,
+	"get_address_book":  true,
+}
+
+// cacheInvalidations are the cached methods made stale by successful mutating
+// calls. Entries of get_address are only dropped for the account of the call.
+// Spending and refreshing change the balances returned by get_accounts.
+var cacheInvalidations = map[string][]string{
+	"create_address":              {"get_address", "get_address_index", "get_accounts"},
+	"label_address":               {"get_address", "get_accounts"},
+	"create_account":              {"get_accounts"},
+	"label_account":               {"get_address", "get_accounts"},
+	"tag_accounts":                {"get_accounts", "get_account_tags"},
+	"untag_accounts":              {"get_accounts", "get_account_tags"},
+	"set_account_tag_description": {"get_account_tags"},
+	"add_address_book":            {"get_address_book"},
+	"delete_address_book":         {"get_address_book"},
+	"transfer":                    {"get_accounts"},
+	"transfer_split":              {"get_accounts"},
+	"sweep_all":                   {"get_accounts"},
+	"sweep_single":                {"get_accounts"},
+	"sweep_dust":                  {"get_accounts"},
+	"relay_tx":                    {"get_accounts"},
+	"submit_transfer":             {"get_accounts"},
+	"submit_multisig":             {"get_accounts"},
+	"refresh":                     {"get_accounts"},
+	"rescan_blockchain":           {"get_accounts"},
+	"rescan_spent":                {"get_accounts"},
+	"import_key_images":           {"get_accounts"},
+	"import_outputs":              {"get_accounts"},
+}
+
+// cachePurges are the calls switching or replacing the wallet, dropping every entry.
+var cachePurges = map[string]bool{
+	"create_wallet":                true,
+	"open_wallet":                  true,
+	"close_wallet":                 true,
+	"stop_wallet":                  true,
+	"generate_from_keys":           true,
+	"restore_deterministic_wallet": true,
+}
+
+// CacheConfig configures a Cache.
+type CacheConfig struct {
+	// TTL is how long results are kept. Defaults to 1 minute.
+	TTL time.Duration
+	// MethodTTLs overrides TTL for single methods, keyed by method name.
+	MethodTTLs map[string]time.Duration
+}
+
+// CacheStats are the hits and misses of a cached method.
+type CacheStats struct {
+	Hits   uint64
+	Misses uint64
+}
+
+// Cache is a read-through cache of slow changing wallet data: the results of
+// get_address, get_address_index, get_accounts, get_account_tags and
+// get_address_book. Entries expire after their TTL, and are dropped as soon as
+// a call changing them succeeds (eg. create_address drops get_address of its
+// account), or the wallet is switched.
+//
+// A Cache is installed with its Interceptor. Interceptors placed after it only
+// see the calls the cache missed.
+type Cache struct {
+	cfg CacheConfig
+
+	mu      sync.Mutex
+	entries map[cacheKey]*cacheEntry
+	stats   map[string]*CacheStats
+}
+
+type cacheKey struct {
+	method string
+	params string
+}
+
+type cacheEntry struct {
+	result  json.RawMessage
+	account *uint64
+	expires time.Time
+}
+
+// NewCache returns an empty cache.
+func NewCache(cfg CacheConfig) *Cache {
+	if cfg.TTL <= 0 {
+		cfg.TTL = time.Minute
+	}
+	return &Cache{
+		cfg:     cfg,
+		entries: make(map[cacheKey]*cacheEntry),
+		stats:   make(map[string]*CacheStats),
+	}
+}
+
+// Stats returns the hits and misses by method.
+func (c *Cache) Stats() map[string]CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	stats := make(map[string]CacheStats, len(c.stats))
+	for method, s := range c.stats {
+		stats[method] = *s
+	}
+	return stats
+}
+
+// Purge drops every entry.
+func (c *Cache) Purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = make(map[cacheKey]*cacheEntry)
+}
+
+// Interceptor returns the interceptor answering calls from the cache.
+func (c *Cache) Interceptor() Interceptor {
+	return func(ctx context.Context, method string, params, result interface{}, next Invoker) error {
+		if !cachedMethods[method] {
+			err := next(ctx, method, params, result)
+			if err == nil {
+				c.invalidate(method, params)
+			}
+			return err
+		}
+
+		b, err := json.Marshal(params)
+		if err != nil {
+			return next(ctx, method, params, result)
+		}
+		key := cacheKey{method: method, params: string(b)}
+		if c.get(key, result) {
+			return nil
+		}
+		if err := next(ctx, method, params, result); err != nil {
+			return err
+		}
+		c.put(key, params, result)
+		return nil
+	}
+}
+
+// get decodes a live entry into result, counting the hit or miss.
+func (c *Cache) get(key cacheKey, result interface{}) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	s := c.stats[key.method]
+	if s == nil {
+		s = &CacheStats{}
+		c.stats[key.method] = s
+	}
+	e, ok := c.entries[key]
+	if ok && time.Now().After(e.expires) {
+		delete(c.entries, key)
+		ok = false
+	}
+	if ok && (result == nil || json.Unmarshal(e.result, result) == nil) {
+		s.Hits++
+		return true
+	}
+	s.Misses++
+	return false
+}
+
+func (c *Cache) put(key cacheKey, params, result interface{}) {
+	b, err := json.Marshal(result)
+	if err != nil {
+		return
+	}
+	ttl, ok := c.cfg.MethodTTLs[key.method]
+	if !ok {
+		ttl = c.cfg.TTL
+	}
+	account, scoped := accountOf(params)
+	e := &cacheEntry{
+		result:  b,
+		expires: time.Now().Add(ttl),
+	}
+	if scoped {
+		e.account = &account
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[key] = e
+}
+
+// invalidate drops the entries made stale by a successful call of method.
+func (c *Cache) invalidate(method string, params interface{}) {
+	if cachePurges[method] {
+		c.Purge()
+		return
+	}
+	stale, ok := cacheInvalidations[method]
+	if !ok {
+		return
+	}
+	account, scoped := accountOf(params)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key, e := range c.entries {
+		for _, m := range stale {
+			if key.method == m && (!scoped || e.account == nil || *e.account == account) {
+				delete(c.entries, key)
+			}
+		}
+	}
+}
+
+// accountOf returns the account a call is about, for the calls
+// whose cache entries are scoped to an account.
+func accountOf(params interface{}) (uint64, bool) {
+	switch req := params.(type) {
+	case *RequestGetAddress:
+		if req != nil {
+			return req.AccountIndex, true
+		}
+	case *RequestCreateAddress:
+		if req != nil {
+			return req.AccountIndex, true
+		}
+	case *RequestLabelAddress:
+		if req != nil {
+			return req.Index.Major, true
+		}
+	case *RequestLabelAccount:
+		if req != nil {
+			return req.AccountIndex, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,97 @@
+package wallet
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCache(t *testing.T) {
+	var mu sync.Mutex
+	calls := make(map[string]int)
+	srv := newTestServer(t, func(req *rpcRequest) interface{} {
+		mu.Lock()
+		defer mu.Unlock()
+		calls[req.Method]++
+		switch req.Method {
+		case "get_address":
+			return H{"address": "4abc", "addresses": []H{{"address": "4abc", "label": "Primary account"}}}
+		case "get_accounts":
+			return H{"total_balance": calls[req.Method]}
+		}
+		return H{}
+	})
+	cache := NewCache(CacheConfig{MethodTTLs: map[string]time.Duration{"get_accounts": time.Millisecond}})
+	cl := New(Config{Address: srv.URL, Interceptors: []Interceptor{cache.Interceptor()}})
+
+	for i := 0; i < 2; i++ {
+		resp, err := cl.GetAddress(&RequestGetAddress{AccountIndex: 0})
+		assert.NoError(t, err)
+		assert.Equal(t, "4abc", resp.Address)
+		assert.Equal(t, "Primary account", resp.Addresses[0].Label)
+	}
+	_, err := cl.GetAddress(&RequestGetAddress{AccountIndex: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, calls["get_address"])
+
+	// labeling an address of account 1 keeps the entry of account 0
+	label := &RequestLabelAddress{Label: "x"}
+	label.Index.Major = 1
+	assert.NoError(t, cl.LabelAddress(label))
+	cl.GetAddress(&RequestGetAddress{AccountIndex: 0})
+	cl.GetAddress(&RequestGetAddress{AccountIndex: 1})
+	assert.Equal(t, 3, calls["get_address"])
+
+	// expired
+	cl.GetAccounts(&RequestGetAccounts{})
+	time.Sleep(5 * time.Millisecond)
+	resp, err := cl.GetAccounts(&RequestGetAccounts{})
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(2), resp.TotalBalance)
+
+	// switching wallets drops everything
+	assert.NoError(t, cl.OpenWallet(&RequestOpenWallet{Filename: "w"}))
+	cl.GetAddress(&RequestGetAddress{AccountIndex: 0})
+	assert.Equal(t, 4, calls["get_address"])
+
+	assert.Equal(t, CacheStats{Hits: 2, Misses: 4}, cache.Stats()["get_address"])
+	assert.Equal(t, CacheStats{Hits: 0, Misses: 2}, cache.Stats()["get_accounts"])
+}
+
+func TestCacheInvalidation(t *testing.T) {
+	var mu sync.Mutex
+	calls := make(map[string]int)
+	srv := newTestServer(t, func(req *rpcRequest) interface{} {
+		mu.Lock()
+		defer mu.Unlock()
+		calls[req.Method]++
+		return H{"address": "4abc", "total_balance": 10 - calls["transfer"]}
+	})
+	cache := NewCache(CacheConfig{})
+	cl := New(Config{Address: srv.URL, Interceptors: []Interceptor{cache.Interceptor()}})
+
+	// nil requests are cached and invalidate without being about an account
+	for i := 0; i < 2; i++ {
+		_, err := cl.GetAddress(nil)
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, 1, calls["get_address"])
+	_, err := cl.CreateAddress(nil)
+	assert.NoError(t, err)
+	assert.NoError(t, cl.LabelAddress(nil))
+	assert.NoError(t, cl.LabelAccount(nil))
+	cl.GetAddress(nil)
+	assert.Equal(t, 2, calls["get_address"])
+
+	// a payout changes the balances
+	resp, err := cl.GetAccounts(&RequestGetAccounts{})
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(10), resp.TotalBalance)
+	_, err = cl.Transfer(&RequestTransfer{})
+	assert.NoError(t, err)
+	resp, err = cl.GetAccounts(&RequestGetAccounts{})
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(9), resp.TotalBalance)
+}
@@ -2,10 +2,13 @@ package wallet
 
 import (
 	"bytes"
+	"context"
 	"fmt"
+	"io"
 	"net/http"
+	"time"
 
-	"github.com/gorilla/rpc/v2/json2"
+	"github.com/omani/go-monero-rpc-client/httpdigest"
 )
 
 // Client is a monero-wallet-rpc client.
@@ -74,7 +77,7 @@ type Client interface {
 	StopWallet() error
 	// Rescan the blockchain from scratch, losing any information which can not be recovered from the blockchain itself.
 	// This includes destination addresses, tx secret keys, tx notes, etc.
-	RescanBlockchain() error
+	RescanBlockchain(*RequestRescanBlockchain) error
 	// Set arbitrary string notes for transactions.
 	SetTxNotes(*RequestSetTxNotes) error
 	// Get string notes for transactions.
@@ -101,6 +104,9 @@ type Client interface {
 	CheckReserveProof(*RequestCheckReserveProof) (*ResponseCheckReserveProof, error)
 	// Returns a list of transfers.
 	GetTransfers(*RequestGetTransfers) (*ResponseGetTransfers, error)
+	// Same as GetTransfers, but hands the transfers to fn one at a time as they are decoded,
+	// without holding the whole response in memory.
+	StreamTransfers(req *RequestGetTransfers, fn func(*Transfer) error) error
 	// Show information about a transfer to/from this address.
 	GetTransferByTxID(*RequestGetTransferByTxID) (*ResponseGetTransferByTxID, error)
 	// Sign a string.
@@ -127,6 +133,8 @@ type Client interface {
 	DeleteAddressBook(*RequestDeleteAddressBook) error
 	// Refresh a wallet after openning.
 	Refresh(*RequestRefresh) (*ResponseRefresh, error)
+	// Set whether and how often to automatically refresh the current wallet.
+	AutoRefresh(*RequestAutoRefresh) error
 	// Rescan the blockchain for spent outputs.
 	RescanSpent() error
 	// Start mining in the Monero daemon.
@@ -139,6 +147,8 @@ type Client interface {
 	CreateWallet(*RequestCreateWallet) error
 	// Restores a wallet from a given wallet address, view key, and optional spend key. You need to have set the argument "–wallet-dir" when launching monero-wallet-rpc to make this work.
 	GenerateFromKeys(*RequestGenerateFromKeys) (*ResponseGenerateFromKeys, error)
+	// Create and open a wallet on the RPC server from an existing mnemonic phrase. You need to have set the argument "–wallet-dir" when launching monero-wallet-rpc to make this work.
+	RestoreDeterministicWallet(*RequestRestoreDeterministicWallet) (*ResponseRestoreDeterministicWallet, error)
 	// Open a wallet. You need to have set the argument "–wallet-dir" when launching monero-wallet-rpc to make this work.
 	OpenWallet(*RequestOpenWallet) error
 	// Close the currently opened wallet, after trying to save it.
@@ -163,642 +173,1488 @@ type Client interface {
 	SubmitMultisig(*RequestSubmitMultisig) (*ResponseSubmitMultisig, error)
 	// Get RPC version Major & Minor integer-format, where Major is the first 16 bits and Minor the last 16 bits.
 	GetVersion() (*ResponseGetVersion, error)
+	// Freeze an output by key image, so it is not used in transactions.
+	Freeze(*RequestFreeze) error
+	// Thaw a frozen output, so it can be used in transactions again.
+	Thaw(*RequestThaw) error
+	// Check whether an output is frozen.
+	Frozen(*RequestFrozen) (*ResponseFrozen, error)
+	// Connect the RPC server to a Monero daemon.
+	SetDaemon(*RequestSetDaemon) error
+	// Estimate the size and weight of a transaction with the given number of inputs and outputs.
+	EstimateTxSizeAndWeight(*RequestEstimateTxSizeAndWeight) (*ResponseEstimateTxSizeAndWeight, error)
+	// Get the priority used by transfers made with the default priority.
+	GetDefaultFeePriority() (*ResponseGetDefaultFeePriority, error)
+}
+
+// ContextClient is a monero-wallet-rpc client whose methods take a context.Context.
+// The context is carried through to the underlying HTTP request, so a call can be
+// cancelled or bounded by a deadline. The embedded Client methods behave like their
+// Context counterparts called with context.Background().
+type ContextClient interface {
+	Client
+
+	// Return the wallet's balance.
+	GetBalanceContext(context.Context, *RequestGetBalance) (*ResponseGetBalance, error)
+	// Return the wallet's addresses for an account. Optionally filter for specific set of subaddresses.
+	GetAddressContext(context.Context, *RequestGetAddress) (*ResponseGetAddress, error)
+	// Get account and address indexes from a specific (sub)address
+	GetAddressIndexContext(context.Context, *RequestGetAddressIndex) (*ResponseGetAddressIndex, error)
+	// Create a new address for an account. Optionally, label the new address.
+	CreateAddressContext(context.Context, *RequestCreateAddress) (*ResponseCreateAddress, error)
+	// Label an address.
+	LabelAddressContext(context.Context, *RequestLabelAddress) error
+	// Validate an address.
+	ValidateAddressContext(context.Context, *RequestValidateAddress) (*ResponseValidateAddress, error)
+	// Get all accounts for a wallet. Optionally filter accounts by tag.
+	GetAccountsContext(context.Context, *RequestGetAccounts) (*ResponseGetAccounts, error)
+	// Create a new account with an optional label.
+	CreateAccountContext(context.Context, *RequestCreateAccount) (*ResponseCreateAccount, error)
+	// Label an account.
+	LabelAccountContext(context.Context, *RequestLabelAccount) error
+	// Get a list of user-defined account tags.
+	GetAccountTagsContext(context.Context) (*ResponseGetAccountTags, error)
+	// Apply a filtering tag to a list of accounts.
+	TagAccountsContext(context.Context, *RequestTagAccounts) error
+	// Remove filtering tag from a list of accounts.
+	UntagAccountsContext(context.Context, *RequestUntagAccounts) error
+	// Set description for an account tag.
+	SetAccountTagDescriptionContext(context.Context, *RequestSetAccountTagDescription) error
+	// Returns the wallet's current block height.
+	GetHeightContext(context.Context) (*ResponseGetHeight, error)
+	// Send monero to a number of recipients.
+	TransferContext(context.Context, *RequestTransfer) (*ResponseTransfer, error)
+	// Same as transfer, but can split into more than one tx if necessary.
+	TransferSplitContext(context.Context, *RequestTransferSplit) (*ResponseTransferSplit, error)
+	// Sign a transaction created on a read-only wallet (in cold-signing process)
+	SignTransferContext(context.Context, *RequestSignTransfer) (*ResponseSignTransfer, error)
+	// Submit a previously signed transaction on a read-only wallet (in cold-signing process).
+	SubmitTransferContext(context.Context, *RequestSubmitTransfer) (*ResponseSubmitTransfer, error)
+	// Send all dust outputs back to the wallet's, to make them easier to spend (and mix).
+	SweepDustContext(context.Context, *RequestSweepDust) (*ResponseSweepDust, error)
+	// Send all unlocked balance to an address.
+	SweepAllContext(context.Context, *RequestSweepAll) (*ResponseSweepAll, error)
+	// Send all of a specific unlocked output to an address.
+	SweepSingleContext(context.Context, *RequestSweepSingle) (*ResponseSweepSingle, error)
+	// Relay a transaction previously created with "do_not_relay":true.
+	RelayTxContext(context.Context, *RequestRelayTx) (*ResponseRelayTx, error)
+	// Save the wallet file.
+	StoreContext(context.Context) error
+	// Get a list of incoming payments using a given payment id.
+	GetPaymentsContext(context.Context, *RequestGetPayments) (*ResponseGetPayments, error)
+	// Get a list of incoming payments using a given payment id, or a list of payments ids, from a given height.
+	// This method is the preferred method over get_payments because it has the same functionality but is more extendable.
+	// Either is fine for looking up transactions by a single payment ID.
+	GetBulkPaymentsContext(context.Context, *RequestGetBulkPayments) (*ResponseGetBulkPayments, error)
+	// Return a list of incoming transfers to the wallet.
+	IncomingTransfersContext(context.Context, *RequestIncomingTransfers) (*ResponseIncomingTransfers, error)
+	// Return the spend or view private key.
+	QueryKeyContext(context.Context, *RequestQueryKey) (*ResponseQueryKey, error)
+	// Make an integrated address from the wallet address and a payment id.
+	MakeIntegratedAddressContext(context.Context, *RequestMakeIntegratedAddress) (*ResponseMakeIntegratedAddress, error)
+	// Retrieve the standard address and payment id corresponding to an integrated address.
+	SplitIntegratedAddressContext(context.Context, *RequestSplitIntegratedAddress) (*ResponseSplitIntegratedAddress, error)
+	// Stops the wallet, storing the current state.
+	StopWalletContext(context.Context) error
+	// Rescan the blockchain from scratch, losing any information which can not be recovered from the blockchain itself.
+	// This includes destination addresses, tx secret keys, tx notes, etc.
+	RescanBlockchainContext(context.Context, *RequestRescanBlockchain) error
+	// Set arbitrary string notes for transactions.
+	SetTxNotesContext(context.Context, *RequestSetTxNotes) error
+	// Get string notes for transactions.
+	GetTxNotesContext(context.Context, *RequestGetTxNotes) (*ResponseGetTxNotes, error)
+	// Set arbitrary attribute.
+	SetAttributeContext(context.Context, *RequestSetAttribute) error
+	// Get attribute value by name.
+	GetAttributeContext(context.Context, *RequestGetAttribute) (*ResponseGetAttribute, error)
+	// Get transaction secret key from transaction id.
+	GetTxKeyContext(context.Context, *RequestGetTxKey) (*ResponseGetTxKey, error)
+	// Check a transaction in the blockchain with its secret key.
+	CheckTxKeyContext(context.Context, *RequestCheckTxKey) (*ResponseCheckTxKey, error)
+	// Get transaction signature to prove it.
+	GetTxProofContext(context.Context, *RequestGetTxProof) (*ResponseGetTxProof, error)
+	// Prove a transaction by checking its signature.
+	CheckTxProofContext(context.Context, *RequestCheckTxProof) (*ResponseCheckTxProof, error)
+	// Generate a signature to prove a spend. Unlike proving a transaction, it does not requires the destination public address.
+	GetSpendProofContext(context.Context, *RequestGetSpendProof) (*ResponseGetSpendProof, error)
+	// Prove a spend using a signature. Unlike proving a transaction, it does not requires the destination public address.
+	CheckSpendProofContext(context.Context, *RequestCheckSpendProof) (*ResponseCheckSpendProof, error)
+	// Generate a signature to prove of an available amount in a wallet.
+	GetReserveProofContext(context.Context, *RequestGetReserveProof) (*ResponseGetReserveProof, error)
+	// Proves a wallet has a disposable reserve using a signature.
+	CheckReserveProofContext(context.Context, *RequestCheckReserveProof) (*ResponseCheckReserveProof, error)
+	// Returns a list of transfers.
+	GetTransfersContext(context.Context, *RequestGetTransfers) (*ResponseGetTransfers, error)
+	// Same as GetTransfers, but hands the transfers to fn one at a time as they are decoded,
+	// without holding the whole response in memory.
+	StreamTransfersContext(ctx context.Context, req *RequestGetTransfers, fn func(*Transfer) error) error
+	// Show information about a transfer to/from this address.
+	GetTransferByTxIDContext(context.Context, *RequestGetTransferByTxID) (*ResponseGetTransferByTxID, error)
+	// Sign a string.
+	SignContext(context.Context, *RequestSign) (*ResponseSign, error)
+	// Verify a signature on a string.
+	VerifyContext(context.Context, *RequestVerify) (*ResponseVerify, error)
+	// Export all outputs in hex format.
+	ExportOutputsContext(context.Context) (*ResponseExportOutputs, error)
+	// Import outputs in hex format.
+	ImportOutputsContext(context.Context, *RequestImportOutputs) (*ResponseImportOutputs, error)
+	// Export a signed set of key images.
+	ExportKeyImagesContext(context.Context) (*ResponseExportKeyImages, error)
+	// Import signed key images list and verify their spent status.
+	ImportKeyImagesContext(context.Context, *RequestImportKeyImages) (*ResponseImportKeyImages, error)
+	// Create a payment URI using the official URI spec.
+	MakeURIContext(context.Context, *RequestMakeURI) (*ResponseMakeURI, error)
+	// Parse a payment URI to get payment information.
+	ParseURIContext(context.Context, *RequestParseURI) (*ResponseParseURI, error)
+	// Retrieves entries from the address book.
+	GetAddressBookContext(context.Context, *RequestGetAddressBook) (*ResponseGetAddressBook, error)
+	// Add an entry to the address book.
+	AddAddressBookContext(context.Context, *RequestAddAddressBook) (*ResponseAddAddressBook, error)
+	// Delete an entry from the address book.
+	DeleteAddressBookContext(context.Context, *RequestDeleteAddressBook) error
+	// Refresh a wallet after openning.
+	RefreshContext(context.Context, *RequestRefresh) (*ResponseRefresh, error)
+	// Set whether and how often to automatically refresh the current wallet.
+	AutoRefreshContext(context.Context, *RequestAutoRefresh) error
+	// Rescan the blockchain for spent outputs.
+	RescanSpentContext(context.Context) error
+	// Start mining in the Monero daemon.
+	StartMiningContext(context.Context, *RequestStartMining) error
+	// Stop mining in the Monero daemon.
+	StopMiningContext(context.Context) error
+	// Get a list of available languages for your wallet's seed.
+	GetLanguagesContext(context.Context) (*ResponseGetLanguages, error)
+	// Create a new wallet. You need to have set the argument "–wallet-dir" when launching monero-wallet-rpc to make this work.
+	CreateWalletContext(context.Context, *RequestCreateWallet) error
+	// Restores a wallet from a given wallet address, view key, and optional spend key. You need to have set the argument "–wallet-dir" when launching monero-wallet-rpc to make this work.
+	GenerateFromKeysContext(context.Context, *RequestGenerateFromKeys) (*ResponseGenerateFromKeys, error)
+	// Create and open a wallet on the RPC server from an existing mnemonic phrase. You need to have set the argument "–wallet-dir" when launching monero-wallet-rpc to make this work.
+	RestoreDeterministicWalletContext(context.Context, *RequestRestoreDeterministicWallet) (*ResponseRestoreDeterministicWallet, error)
+	// Open a wallet. You need to have set the argument "–wallet-dir" when launching monero-wallet-rpc to make this work.
+	OpenWalletContext(context.Context, *RequestOpenWallet) error
+	// Close the currently opened wallet, after trying to save it.
+	CloseWalletContext(context.Context) error
+	// Change a wallet password.
+	ChangeWalletPasswordContext(context.Context, *RequestChangeWalletPassword) error
+	// Check if a wallet is a multisig one.
+	IsMultisigContext(context.Context) (*ResponseIsMultisig, error)
+	// Prepare a wallet for multisig by generating a multisig string to share with peers.
+	PrepareMultisigContext(context.Context) (*ResponsePrepareMultisig, error)
+	// Make a wallet multisig by importing peers multisig string.
+	MakeMultisigContext(context.Context, *RequestMakeMultisig) (*ResponseMakeMultisig, error)
+	// Export multisig info for other participants.
+	ExportMultisigInfoContext(context.Context) (*ResponseExportMultisigInfo, error)
+	// Import multisig info from other participants.
+	ImportMultisigInfoContext(context.Context, *RequestImportMultisigInfo) (*ResponseImportMultisigInfo, error)
+	// Turn this wallet into a multisig wallet, extra step for N-1/N wallets.
+	FinalizeMultisigContext(context.Context, *RequestFinalizeMultisig) (*ResponseFinalizeMultisig, error)
+	// Sign a transaction in multisig.
+	SignMultisigContext(context.Context, *RequestSignMultisig) (*ResponseSignMultisig, error)
+	// Submit a signed multisig transaction.
+	SubmitMultisigContext(context.Context, *RequestSubmitMultisig) (*ResponseSubmitMultisig, error)
+	// Get RPC version Major & Minor integer-format, where Major is the first 16 bits and Minor the last 16 bits.
+	GetVersionContext(context.Context) (*ResponseGetVersion, error)
+	// Freeze an output by key image, so it is not used in transactions.
+	FreezeContext(context.Context, *RequestFreeze) error
+	// Thaw a frozen output, so it can be used in transactions again.
+	ThawContext(context.Context, *RequestThaw) error
+	// Check whether an output is frozen.
+	FrozenContext(context.Context, *RequestFrozen) (*ResponseFrozen, error)
+	// Connect the RPC server to a Monero daemon.
+	SetDaemonContext(context.Context, *RequestSetDaemon) error
+	// Estimate the size and weight of a transaction with the given number of inputs and outputs.
+	EstimateTxSizeAndWeightContext(context.Context, *RequestEstimateTxSizeAndWeight) (*ResponseEstimateTxSizeAndWeight, error)
+	// Get the priority used by transfers made with the default priority.
+	GetDefaultFeePriorityContext(context.Context) (*ResponseGetDefaultFeePriority, error)
+	// Send several calls as one JSON-RPC batch request. Falls back to sending them
+	// one by one if the server does not accept batch requests.
+	BatchCallContext(context.Context, []*BatchElem) error
+	// Open a wallet, run fn with a client bound to it and close the wallet again,
+	// holding the client exclusively meanwhile.
+	WithWallet(ctx context.Context, filename, password string, fn func(Client) error, opts ...SessionOption) error
 }
 
 // New returns a new monero-wallet-rpc client.
 func New(cfg Config) Client {
-	cl := &client{
-		addr:    cfg.Address,
-		headers: cfg.CustomHeaders,
+	return NewContextClient(cfg)
+}
+
+// NewContextClient returns a new monero-wallet-rpc client with context-aware methods.
+func NewContextClient(cfg Config) ContextClient {
+	return &client{
+		ep:        newEndpoint(cfg),
+		codec:     newCodec(cfg.Codec),
+		intercept: ChainInterceptors(cfg.Interceptors...),
+		session:   newSessionLock(),
 	}
-	if cfg.Transport == nil {
-		cl.httpcl = http.DefaultClient
+}
+
+func newEndpoint(cfg Config) *endpoint {
+	ep := &endpoint{
+		addr:     cfg.Address,
+		headers:  cfg.CustomHeaders,
+		timeout:  cfg.Timeout,
+		timeouts: cfg.MethodTimeouts,
+		retry:    cfg.Retry,
+		maxSize:  cfg.MaxResponseSize,
+	}
+	if cfg.Scheduler != nil {
+		ep.sched = newScheduler(cfg.Scheduler)
+	}
+	transport, err := newTransport(cfg)
+	if err != nil {
+		ep.err = err
+	}
+	if cfg.Username != "" {
+		transport = &httpdigest.Transport{
+			Username:  cfg.Username,
+			Password:  cfg.Password,
+			Transport: transport,
+		}
+	}
+	if transport == nil {
+		ep.httpcl = http.DefaultClient
 	} else {
-		cl.httpcl = &http.Client{
-			Transport: cfg.Transport,
+		ep.httpcl = &http.Client{
+			Transport: transport,
+		}
+	}
+	return ep
+}
+
+// newTransport builds the http.RoundTripper of an endpoint from cfg.
+func newTransport(cfg Config) (http.RoundTripper, error) {
+	if cfg.Proxy == "" && cfg.TLS == nil {
+		return cfg.Transport, nil
+	}
+	t, err := httpTransport(cfg.Transport)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Proxy != "" {
+		if err := setProxy(t, cfg); err != nil {
+			return nil, err
+		}
+	}
+	if cfg.TLS != nil {
+		if t.TLSClientConfig, err = cfg.TLS.config(t.TLSClientConfig); err != nil {
+			return nil, err
 		}
 	}
-	return cl
+	return t, nil
+}
+
+// httpTransport returns a copy of rt to be configured, or of
+// http.DefaultTransport if rt is nil.
+func httpTransport(rt http.RoundTripper) (*http.Transport, error) {
+	switch t := rt.(type) {
+	case nil:
+		return http.DefaultTransport.(*http.Transport).Clone(), nil
+	case *http.Transport:
+		return t.Clone(), nil
+	}
+	return nil, fmt.Errorf("custom transport %T can not be combined with proxy or TLS settings", rt)
 }
 
 type client struct {
-	httpcl  *http.Client
-	addr    string
-	headers map[string]string
+	ep        sender
+	codec     *codec
+	intercept Interceptor
+	noBatch   int32
+	session   *sessionLock
+	// inSession is set on the client handed to a WithWallet callback,
+	// which already holds the session lock.
+	inSession bool
+}
+
+// sender posts encoded JSON-RPC payloads to monero-wallet-rpc.
+type sender interface {
+	// send posts a payload carrying calls of the given methods and hands
+	// the response body to decode.
+	send(ctx context.Context, methods []string, payload []byte, decode func(io.Reader) error) error
 }
 
 // Helper function
-func (c *client) do(method string, in, out interface{}) error {
-	payload, err := json2.EncodeClientRequest(method, in)
-	if err != nil {
+func (c *client) do(ctx context.Context, method string, in, out interface{}) error {
+	if !c.inSession {
+		if err := c.session.acquire(ctx); err != nil {
+			return err
+		}
+		defer c.session.release()
+	}
+	if c.intercept != nil {
+		return c.intercept(ctx, method, in, out, c.invoke)
+	}
+	return c.invoke(ctx, method, in, out)
+}
+
+// invoke sends a call bypassing the interceptors.
+func (c *client) invoke(ctx context.Context, method string, in, out interface{}) error {
+	return c.codec.call(ctx, c.ep, method, in, out)
+}
+
+// maxErrorBody is how much of the body of a failed HTTP request is kept in a HTTPError.
+const maxErrorBody = 4 << 10
+
+// endpoint is a single monero-wallet-rpc instance.
+type endpoint struct {
+	httpcl   *http.Client
+	addr     string
+	headers  map[string]string
+	timeout  time.Duration
+	timeouts map[string]time.Duration
+	retry    *RetryPolicy
+	sched    *scheduler
+	maxSize  int64
+	// err is a configuration error returned by every call.
+	err error
+}
+
+// send posts the payload, sending it again according to the retry
+// policy if all of the methods are read-only.
+func (e *endpoint) send(ctx context.Context, methods []string, payload []byte, decode func(io.Reader) error) error {
+	if e.err != nil {
+		return e.err
+	}
+	attempts := 1
+	if e.retry != nil && readOnly(methods) {
+		attempts = e.retry.MaxAttempts
+	}
+	var timeout time.Duration
+	for _, method := range methods {
+		if d := e.methodTimeout(method); d > timeout {
+			timeout = d
+		}
+	}
+	for attempt := 1; ; attempt++ {
+		err := e.attempt(ctx, methods, timeout, payload, decode)
+		if err == nil || attempt >= attempts || ctx.Err() != nil || !IsRetryable(err) {
+			return err
+		}
+		if err := sleepContext(ctx, e.retry.backoff(attempt)); err != nil {
+			return err
+		}
+	}
+}
+
+func (e *endpoint) methodTimeout(method string) time.Duration {
+	if d, ok := e.timeouts[method]; ok {
+		return d
+	}
+	return e.timeout
+}
+
+// attempt posts the payload once it is its turn in the scheduler.
+func (e *endpoint) attempt(ctx context.Context, methods []string, timeout time.Duration, payload []byte, decode func(io.Reader) error) error {
+	if e.sched == nil {
+		return e.post(ctx, timeout, payload, decode)
+	}
+	if err := e.sched.acquire(ctx, methods); err != nil {
 		return err
 	}
+	defer e.sched.release()
+	return e.post(ctx, timeout, payload, decode)
+}
 
-	req, err := http.NewRequest(http.MethodPost, c.addr, bytes.NewBuffer(payload))
+// post sends a single attempt of an encoded payload.
+func (e *endpoint) post(ctx context.Context, timeout time.Duration, payload []byte, decode func(io.Reader) error) error {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.addr, bytes.NewReader(payload))
 	if err != nil {
 		return err
 	}
-	if c.headers != nil {
-		for k, v := range c.headers {
+	if e.headers != nil {
+		for k, v := range e.headers {
 			req.Header.Set(k, v)
 		}
 	}
-	resp, err := c.httpcl.Do(req)
+	resp, err := e.httpcl.Do(req)
 	if err != nil {
 		return err
 	}
+	defer resp.Body.Close()
 	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("http status %v", resp.StatusCode)
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+		return &HTTPError{
+			StatusCode: resp.StatusCode,
+			Body:       body,
+		}
 	}
-	defer resp.Body.Close()
-
-	// in theory this is only done to catch
-	// any monero related errors if
-	// we are not expecting any data back
-	if out == nil {
-		v := &json2.EmptyResponse{}
-		return json2.DecodeClientResponse(resp.Body, v)
+	if e.maxSize > 0 && !streaming(ctx) {
+		if resp.ContentLength > e.maxSize {
+			return fmt.Errorf("%w: %d bytes, limit is %d", ErrResponseTooLarge, resp.ContentLength, e.maxSize)
+		}
+		return decode(newLimitBody(resp.Body, e.maxSize))
 	}
-	return json2.DecodeClientResponse(resp.Body, out)
+	return decode(resp.Body)
 }
 
 // Methods
-func (c *client) GetBalance(req *RequestGetBalance) (resp *ResponseGetBalance, err error) {
-	err = c.do("get_balance", &req, &resp)
+func (c *client) GetBalance(req *RequestGetBalance) (*ResponseGetBalance, error) {
+	return c.GetBalanceContext(context.Background(), req)
+}
+
+func (c *client) GetBalanceContext(ctx context.Context, req *RequestGetBalance) (resp *ResponseGetBalance, err error) {
+	resp = &ResponseGetBalance{}
+	err = c.do(ctx, "get_balance", req, resp)
 	if err != nil {
 		return nil, err
 	}
 	return
 }
 
-func (c *client) GetAddress(req *RequestGetAddress) (resp *ResponseGetAddress, err error) {
-	err = c.do("get_address", &req, &resp)
+func (c *client) GetAddress(req *RequestGetAddress) (*ResponseGetAddress, error) {
+	return c.GetAddressContext(context.Background(), req)
+}
+
+func (c *client) GetAddressContext(ctx context.Context, req *RequestGetAddress) (resp *ResponseGetAddress, err error) {
+	resp = &ResponseGetAddress{}
+	err = c.do(ctx, "get_address", req, resp)
 	if err != nil {
 		return nil, err
 	}
 	return
 }
 
-func (c *client) GetAddressIndex(req *RequestGetAddressIndex) (resp *ResponseGetAddressIndex, err error) {
-	err = c.do("get_address_index", &req, &resp)
+func (c *client) GetAddressIndex(req *RequestGetAddressIndex) (*ResponseGetAddressIndex, error) {
+	return c.GetAddressIndexContext(context.Background(), req)
+}
+
+func (c *client) GetAddressIndexContext(ctx context.Context, req *RequestGetAddressIndex) (resp *ResponseGetAddressIndex, err error) {
+	resp = &ResponseGetAddressIndex{}
+	err = c.do(ctx, "get_address_index", req, resp)
 	if err != nil {
 		return nil, err
 	}
 	return
 }
 
-func (c *client) CreateAddress(req *RequestCreateAddress) (resp *ResponseCreateAddress, err error) {
-	err = c.do("create_address", &req, &resp)
+func (c *client) CreateAddress(req *RequestCreateAddress) (*ResponseCreateAddress, error) {
+	return c.CreateAddressContext(context.Background(), req)
+}
+
+func (c *client) CreateAddressContext(ctx context.Context, req *RequestCreateAddress) (resp *ResponseCreateAddress, err error) {
+	resp = &ResponseCreateAddress{}
+	err = c.do(ctx, "create_address", req, resp)
 	if err != nil {
 		return nil, err
 	}
 	return
 }
 
-func (c *client) LabelAddress(req *RequestLabelAddress) (err error) {
-	err = c.do("label_address", req, nil)
+func (c *client) LabelAddress(req *RequestLabelAddress) error {
+	return c.LabelAddressContext(context.Background(), req)
+}
+
+func (c *client) LabelAddressContext(ctx context.Context, req *RequestLabelAddress) (err error) {
+	err = c.do(ctx, "label_address", req, nil)
 	if err != nil {
 		return err
 	}
 	return
 }
 
-func (c *client) ValidateAddress(req *RequestValidateAddress) (resp *ResponseValidateAddress, err error) {
-	err = c.do("validate_address", &req, &resp)
+func (c *client) ValidateAddress(req *RequestValidateAddress) (*ResponseValidateAddress, error) {
+	return c.ValidateAddressContext(context.Background(), req)
+}
+
+func (c *client) ValidateAddressContext(ctx context.Context, req *RequestValidateAddress) (resp *ResponseValidateAddress, err error) {
+	resp = &ResponseValidateAddress{}
+	err = c.do(ctx, "validate_address", req, resp)
 	if err != nil {
 		return nil, err
 	}
 	return
 }
 
-func (c *client) GetAccounts(req *RequestGetAccounts) (resp *ResponseGetAccounts, err error) {
-	err = c.do("get_accounts", &req, &resp)
+func (c *client) GetAccounts(req *RequestGetAccounts) (*ResponseGetAccounts, error) {
+	return c.GetAccountsContext(context.Background(), req)
+}
+
+func (c *client) GetAccountsContext(ctx context.Context, req *RequestGetAccounts) (resp *ResponseGetAccounts, err error) {
+	resp = &ResponseGetAccounts{}
+	err = c.do(ctx, "get_accounts", req, resp)
 	if err != nil {
 		return nil, err
 	}
 	return
 }
 
-func (c *client) CreateAccount(req *RequestCreateAccount) (resp *ResponseCreateAccount, err error) {
-	err = c.do("create_account", &req, &resp)
+func (c *client) CreateAccount(req *RequestCreateAccount) (*ResponseCreateAccount, error) {
+	return c.CreateAccountContext(context.Background(), req)
+}
+
+func (c *client) CreateAccountContext(ctx context.Context, req *RequestCreateAccount) (resp *ResponseCreateAccount, err error) {
+	resp = &ResponseCreateAccount{}
+	err = c.do(ctx, "create_account", req, resp)
 	if err != nil {
 		return nil, err
 	}
 	return
 }
 
-func (c *client) LabelAccount(req *RequestLabelAccount) (err error) {
-	err = c.do("label_account", &req, nil)
+func (c *client) LabelAccount(req *RequestLabelAccount) error {
+	return c.LabelAccountContext(context.Background(), req)
+}
+
+func (c *client) LabelAccountContext(ctx context.Context, req *RequestLabelAccount) (err error) {
+	err = c.do(ctx, "label_account", req, nil)
 	if err != nil {
 		return err
 	}
 	return
 }
-func (c *client) GetAccountTags() (resp *ResponseGetAccountTags, err error) {
-	err = c.do("get_account_tags", nil, &resp)
+
+func (c *client) GetAccountTags() (*ResponseGetAccountTags, error) {
+	return c.GetAccountTagsContext(context.Background())
+}
+
+func (c *client) GetAccountTagsContext(ctx context.Context) (resp *ResponseGetAccountTags, err error) {
+	resp = &ResponseGetAccountTags{}
+	err = c.do(ctx, "get_account_tags", nil, resp)
 	if err != nil {
 		return nil, err
 	}
 	return
 }
 
-func (c *client) TagAccounts(req *RequestTagAccounts) (err error) {
-	err = c.do("tag_accounts", &req, nil)
+func (c *client) TagAccounts(req *RequestTagAccounts) error {
+	return c.TagAccountsContext(context.Background(), req)
+}
+
+func (c *client) TagAccountsContext(ctx context.Context, req *RequestTagAccounts) (err error) {
+	err = c.do(ctx, "tag_accounts", req, nil)
 	if err != nil {
 		return err
 	}
 	return
 }
-func (c *client) UntagAccounts(req *RequestUntagAccounts) (err error) {
-	err = c.do("untag_accounts", &req, nil)
+
+func (c *client) UntagAccounts(req *RequestUntagAccounts) error {
+	return c.UntagAccountsContext(context.Background(), req)
+}
+
+func (c *client) UntagAccountsContext(ctx context.Context, req *RequestUntagAccounts) (err error) {
+	err = c.do(ctx, "untag_accounts", req, nil)
 	if err != nil {
 		return err
 	}
 	return
 }
-func (c *client) SetAccountTagDescription(req *RequestSetAccountTagDescription) (err error) {
-	err = c.do("set_account_tag_description", &req, nil)
+
+func (c *client) SetAccountTagDescription(req *RequestSetAccountTagDescription) error {
+	return c.SetAccountTagDescriptionContext(context.Background(), req)
+}
+
+func (c *client) SetAccountTagDescriptionContext(ctx context.Context, req *RequestSetAccountTagDescription) (err error) {
+	err = c.do(ctx, "set_account_tag_description", req, nil)
 	if err != nil {
 		return err
 	}
 	return
 }
-func (c *client) GetHeight() (resp *ResponseGetHeight, err error) {
-	err = c.do("get_height", nil, &resp)
+
+func (c *client) GetHeight() (*ResponseGetHeight, error) {
+	return c.GetHeightContext(context.Background())
+}
+
+func (c *client) GetHeightContext(ctx context.Context) (resp *ResponseGetHeight, err error) {
+	resp = &ResponseGetHeight{}
+	err = c.do(ctx, "get_height", nil, resp)
 	if err != nil {
 		return nil, err
 	}
 	return
 }
 
-func (c *client) Transfer(req *RequestTransfer) (resp *ResponseTransfer, err error) {
-	err = c.do("transfer", &req, &resp)
+func (c *client) Transfer(req *RequestTransfer) (*ResponseTransfer, error) {
+	return c.TransferContext(context.Background(), req)
+}
+
+func (c *client) TransferContext(ctx context.Context, req *RequestTransfer) (resp *ResponseTransfer, err error) {
+	resp = &ResponseTransfer{}
+	err = c.do(ctx, "transfer", req, resp)
 	if err != nil {
 		return nil, err
 	}
 	return
 }
 
-func (c *client) TransferSplit(req *RequestTransferSplit) (resp *ResponseTransferSplit, err error) {
-	err = c.do("transfer_split", &req, &resp)
+func (c *client) TransferSplit(req *RequestTransferSplit) (*ResponseTransferSplit, error) {
+	return c.TransferSplitContext(context.Background(), req)
+}
+
+func (c *client) TransferSplitContext(ctx context.Context, req *RequestTransferSplit) (resp *ResponseTransferSplit, err error) {
+	resp = &ResponseTransferSplit{}
+	err = c.do(ctx, "transfer_split", req, resp)
 	if err != nil {
 		return nil, err
 	}
 	return
 }
 
-func (c *client) SignTransfer(req *RequestSignTransfer) (resp *ResponseSignTransfer, err error) {
-	err = c.do("sign_transfer", &req, &resp)
+func (c *client) SignTransfer(req *RequestSignTransfer) (*ResponseSignTransfer, error) {
+	return c.SignTransferContext(context.Background(), req)
+}
+
+func (c *client) SignTransferContext(ctx context.Context, req *RequestSignTransfer) (resp *ResponseSignTransfer, err error) {
+	resp = &ResponseSignTransfer{}
+	err = c.do(ctx, "sign_transfer", req, resp)
 	if err != nil {
 		return nil, err
 	}
 	return
 }
 
-func (c *client) SubmitTransfer(req *RequestSubmitTransfer) (resp *ResponseSubmitTransfer, err error) {
-	err = c.do("submit_transfer", &req, &resp)
+func (c *client) SubmitTransfer(req *RequestSubmitTransfer) (*ResponseSubmitTransfer, error) {
+	return c.SubmitTransferContext(context.Background(), req)
+}
+
+func (c *client) SubmitTransferContext(ctx context.Context, req *RequestSubmitTransfer) (resp *ResponseSubmitTransfer, err error) {
+	resp = &ResponseSubmitTransfer{}
+	err = c.do(ctx, "submit_transfer", req, resp)
 	if err != nil {
 		return nil, err
 	}
 	return
 }
 
-func (c *client) SweepDust(req *RequestSweepDust) (resp *ResponseSweepDust, err error) {
-	err = c.do("sweep_dust", &req, &resp)
+func (c *client) SweepDust(req *RequestSweepDust) (*ResponseSweepDust, error) {
+	return c.SweepDustContext(context.Background(), req)
+}
+
+func (c *client) SweepDustContext(ctx context.Context, req *RequestSweepDust) (resp *ResponseSweepDust, err error) {
+	resp = &ResponseSweepDust{}
+	err = c.do(ctx, "sweep_dust", req, resp)
 	if err != nil {
 		return nil, err
 	}
 	return
 }
 
-func (c *client) SweepAll(req *RequestSweepAll) (resp *ResponseSweepAll, err error) {
-	err = c.do("sweep_all", &req, &resp)
+func (c *client) SweepAll(req *RequestSweepAll) (*ResponseSweepAll, error) {
+	return c.SweepAllContext(context.Background(), req)
+}
+
+func (c *client) SweepAllContext(ctx context.Context, req *RequestSweepAll) (resp *ResponseSweepAll, err error) {
+	resp = &ResponseSweepAll{}
+	err = c.do(ctx, "sweep_all", req, resp)
 	if err != nil {
 		return nil, err
 	}
 	return
 }
 
-func (c *client) SweepSingle(req *RequestSweepSingle) (resp *ResponseSweepSingle, err error) {
-	err = c.do("sweep_single", &req, &resp)
+func (c *client) SweepSingle(req *RequestSweepSingle) (*ResponseSweepSingle, error) {
+	return c.SweepSingleContext(context.Background(), req)
+}
+
+func (c *client) SweepSingleContext(ctx context.Context, req *RequestSweepSingle) (resp *ResponseSweepSingle, err error) {
+	resp = &ResponseSweepSingle{}
+	err = c.do(ctx, "sweep_single", req, resp)
 	if err != nil {
 		return nil, err
 	}
 	return
 }
 
-func (c *client) RelayTx(req *RequestRelayTx) (resp *ResponseRelayTx, err error) {
-	err = c.do("relay_tx", &req, &resp)
+func (c *client) RelayTx(req *RequestRelayTx) (*ResponseRelayTx, error) {
+	return c.RelayTxContext(context.Background(), req)
+}
+
+func (c *client) RelayTxContext(ctx context.Context, req *RequestRelayTx) (resp *ResponseRelayTx, err error) {
+	resp = &ResponseRelayTx{}
+	err = c.do(ctx, "relay_tx", req, resp)
 	if err != nil {
 		return nil, err
 	}
 	return
 }
 
-func (c *client) Store() (err error) {
-	err = c.do("store", nil, nil)
+func (c *client) Store() error {
+	return c.StoreContext(context.Background())
+}
+
+func (c *client) StoreContext(ctx context.Context) (err error) {
+	err = c.do(ctx, "store", nil, nil)
 	if err != nil {
 		return err
 	}
 	return
 }
-func (c *client) GetPayments(req *RequestGetPayments) (resp *ResponseGetPayments, err error) {
-	err = c.do("get_payments", &req, &resp)
+
+func (c *client) GetPayments(req *RequestGetPayments) (*ResponseGetPayments, error) {
+	return c.GetPaymentsContext(context.Background(), req)
+}
+
+func (c *client) GetPaymentsContext(ctx context.Context, req *RequestGetPayments) (resp *ResponseGetPayments, err error) {
+	resp = &ResponseGetPayments{}
+	err = c.do(ctx, "get_payments", req, resp)
 	if err != nil {
 		return nil, err
 	}
 	return
 }
 
-func (c *client) GetBulkPayments(req *RequestGetBulkPayments) (resp *ResponseGetBulkPayments, err error) {
-	err = c.do("get_bulk_payments", &req, &resp)
+func (c *client) GetBulkPayments(req *RequestGetBulkPayments) (*ResponseGetBulkPayments, error) {
+	return c.GetBulkPaymentsContext(context.Background(), req)
+}
+
+func (c *client) GetBulkPaymentsContext(ctx context.Context, req *RequestGetBulkPayments) (resp *ResponseGetBulkPayments, err error) {
+	resp = &ResponseGetBulkPayments{}
+	err = c.do(ctx, "get_bulk_payments", req, resp)
 	if err != nil {
 		return nil, err
 	}
 	return
 }
 
-func (c *client) IncomingTransfers(req *RequestIncomingTransfers) (resp *ResponseIncomingTransfers, err error) {
-	err = c.do("incoming_transfers", &req, &resp)
+func (c *client) IncomingTransfers(req *RequestIncomingTransfers) (*ResponseIncomingTransfers, error) {
+	return c.IncomingTransfersContext(context.Background(), req)
+}
+
+func (c *client) IncomingTransfersContext(ctx context.Context, req *RequestIncomingTransfers) (resp *ResponseIncomingTransfers, err error) {
+	resp = &ResponseIncomingTransfers{}
+	err = c.do(ctx, "incoming_transfers", req, resp)
 	if err != nil {
 		return nil, err
 	}
 	return
 }
 
-func (c *client) QueryKey(req *RequestQueryKey) (resp *ResponseQueryKey, err error) {
-	err = c.do("query_key", &req, &resp)
+func (c *client) QueryKey(req *RequestQueryKey) (*ResponseQueryKey, error) {
+	return c.QueryKeyContext(context.Background(), req)
+}
+
+func (c *client) QueryKeyContext(ctx context.Context, req *RequestQueryKey) (resp *ResponseQueryKey, err error) {
+	resp = &ResponseQueryKey{}
+	err = c.do(ctx, "query_key", req, resp)
 	if err != nil {
 		return nil, err
 	}
 	return
 }
 
-func (c *client) MakeIntegratedAddress(req *RequestMakeIntegratedAddress) (resp *ResponseMakeIntegratedAddress, err error) {
-	err = c.do("make_integrated_address", &req, &resp)
+func (c *client) MakeIntegratedAddress(req *RequestMakeIntegratedAddress) (*ResponseMakeIntegratedAddress, error) {
+	return c.MakeIntegratedAddressContext(context.Background(), req)
+}
+
+func (c *client) MakeIntegratedAddressContext(ctx context.Context, req *RequestMakeIntegratedAddress) (resp *ResponseMakeIntegratedAddress, err error) {
+	resp = &ResponseMakeIntegratedAddress{}
+	err = c.do(ctx, "make_integrated_address", req, resp)
 	if err != nil {
 		return nil, err
 	}
 	return
 }
 
-func (c *client) SplitIntegratedAddress(req *RequestSplitIntegratedAddress) (resp *ResponseSplitIntegratedAddress, err error) {
-	err = c.do("split_integrated_address", &req, &resp)
+func (c *client) SplitIntegratedAddress(req *RequestSplitIntegratedAddress) (*ResponseSplitIntegratedAddress, error) {
+	return c.SplitIntegratedAddressContext(context.Background(), req)
+}
+
+func (c *client) SplitIntegratedAddressContext(ctx context.Context, req *RequestSplitIntegratedAddress) (resp *ResponseSplitIntegratedAddress, err error) {
+	resp = &ResponseSplitIntegratedAddress{}
+	err = c.do(ctx, "split_integrated_address", req, resp)
 	if err != nil {
 		return nil, err
 	}
 	return
 }
 
-func (c *client) StopWallet() (err error) {
-	err = c.do("stop_wallet", nil, nil)
+func (c *client) StopWallet() error {
+	return c.StopWalletContext(context.Background())
+}
+
+func (c *client) StopWalletContext(ctx context.Context) (err error) {
+	err = c.do(ctx, "stop_wallet", nil, nil)
 	if err != nil {
 		return err
 	}
 	return
 }
-func (c *client) RescanBlockchain() (err error) {
-	err = c.do("rescan_blockchain", nil, nil)
+
+func (c *client) RescanBlockchain(req *RequestRescanBlockchain) error {
+	return c.RescanBlockchainContext(context.Background(), req)
+}
+
+func (c *client) RescanBlockchainContext(ctx context.Context, req *RequestRescanBlockchain) (err error) {
+	err = c.do(ctx, "rescan_blockchain", req, nil)
 	if err != nil {
 		return err
 	}
 	return
 }
-func (c *client) SetTxNotes(req *RequestSetTxNotes) (err error) {
-	err = c.do("set_tx_notes", &req, nil)
+
+func (c *client) SetTxNotes(req *RequestSetTxNotes) error {
+	return c.SetTxNotesContext(context.Background(), req)
+}
+
+func (c *client) SetTxNotesContext(ctx context.Context, req *RequestSetTxNotes) (err error) {
+	err = c.do(ctx, "set_tx_notes", req, nil)
 	if err != nil {
 		return err
 	}
 	return
 }
-func (c *client) GetTxNotes(req *RequestGetTxNotes) (resp *ResponseGetTxNotes, err error) {
-	err = c.do("get_tx_notes", &req, &resp)
+
+func (c *client) GetTxNotes(req *RequestGetTxNotes) (*ResponseGetTxNotes, error) {
+	return c.GetTxNotesContext(context.Background(), req)
+}
+
+func (c *client) GetTxNotesContext(ctx context.Context, req *RequestGetTxNotes) (resp *ResponseGetTxNotes, err error) {
+	resp = &ResponseGetTxNotes{}
+	err = c.do(ctx, "get_tx_notes", req, resp)
 	if err != nil {
 		return nil, err
 	}
 	return
 }
 
-func (c *client) SetAttribute(req *RequestSetAttribute) (err error) {
-	err = c.do("set_attribute", &req, nil)
+func (c *client) SetAttribute(req *RequestSetAttribute) error {
+	return c.SetAttributeContext(context.Background(), req)
+}
+
+func (c *client) SetAttributeContext(ctx context.Context, req *RequestSetAttribute) (err error) {
+	err = c.do(ctx, "set_attribute", req, nil)
 	if err != nil {
 		return err
 	}
 	return
 }
-func (c *client) GetAttribute(req *RequestGetAttribute) (resp *ResponseGetAttribute, err error) {
-	err = c.do("get_attribute", &req, &resp)
+
+func (c *client) GetAttribute(req *RequestGetAttribute) (*ResponseGetAttribute, error) {
+	return c.GetAttributeContext(context.Background(), req)
+}
+
+func (c *client) GetAttributeContext(ctx context.Context, req *RequestGetAttribute) (resp *ResponseGetAttribute, err error) {
+	resp = &ResponseGetAttribute{}
+	err = c.do(ctx, "get_attribute", req, resp)
 	if err != nil {
 		return nil, err
 	}
 	return
 }
 
-func (c *client) GetTxKey(req *RequestGetTxKey) (resp *ResponseGetTxKey, err error) {
-	err = c.do("get_tx_key", &req, &resp)
+func (c *client) GetTxKey(req *RequestGetTxKey) (*ResponseGetTxKey, error) {
+	return c.GetTxKeyContext(context.Background(), req)
+}
+
+func (c *client) GetTxKeyContext(ctx context.Context, req *RequestGetTxKey) (resp *ResponseGetTxKey, err error) {
+	resp = &ResponseGetTxKey{}
+	err = c.do(ctx, "get_tx_key", req, resp)
 	if err != nil {
 		return nil, err
 	}
 	return
 }
 
-func (c *client) CheckTxKey(req *RequestCheckTxKey) (resp *ResponseCheckTxKey, err error) {
-	err = c.do("check_tx_key", &req, &resp)
+func (c *client) CheckTxKey(req *RequestCheckTxKey) (*ResponseCheckTxKey, error) {
+	return c.CheckTxKeyContext(context.Background(), req)
+}
+
+func (c *client) CheckTxKeyContext(ctx context.Context, req *RequestCheckTxKey) (resp *ResponseCheckTxKey, err error) {
+	resp = &ResponseCheckTxKey{}
+	err = c.do(ctx, "check_tx_key", req, resp)
 	if err != nil {
 		return nil, err
 	}
 	return
 }
 
-func (c *client) GetTxProof(req *RequestGetTxProof) (resp *ResponseGetTxProof, err error) {
-	err = c.do("get_tx_proof", &req, &resp)
+func (c *client) GetTxProof(req *RequestGetTxProof) (*ResponseGetTxProof, error) {
+	return c.GetTxProofContext(context.Background(), req)
+}
+
+func (c *client) GetTxProofContext(ctx context.Context, req *RequestGetTxProof) (resp *ResponseGetTxProof, err error) {
+	resp = &ResponseGetTxProof{}
+	err = c.do(ctx, "get_tx_proof", req, resp)
 	if err != nil {
 		return nil, err
 	}
 	return
 }
 
-func (c *client) CheckTxProof(req *RequestCheckTxProof) (resp *ResponseCheckTxProof, err error) {
-	err = c.do("check_tx_proof", &req, &resp)
+func (c *client) CheckTxProof(req *RequestCheckTxProof) (*ResponseCheckTxProof, error) {
+	return c.CheckTxProofContext(context.Background(), req)
+}
+
+func (c *client) CheckTxProofContext(ctx context.Context, req *RequestCheckTxProof) (resp *ResponseCheckTxProof, err error) {
+	resp = &ResponseCheckTxProof{}
+	err = c.do(ctx, "check_tx_proof", req, resp)
 	if err != nil {
 		return nil, err
 	}
 	return
 }
 
-func (c *client) GetSpendProof(req *RequestGetSpendProof) (resp *ResponseGetSpendProof, err error) {
-	err = c.do("get_spend_proof", &req, &resp)
+func (c *client) GetSpendProof(req *RequestGetSpendProof) (*ResponseGetSpendProof, error) {
+	return c.GetSpendProofContext(context.Background(), req)
+}
+
+func (c *client) GetSpendProofContext(ctx context.Context, req *RequestGetSpendProof) (resp *ResponseGetSpendProof, err error) {
+	resp = &ResponseGetSpendProof{}
+	err = c.do(ctx, "get_spend_proof", req, resp)
 	if err != nil {
 		return nil, err
 	}
 	return
 }
 
-func (c *client) CheckSpendProof(req *RequestCheckSpendProof) (resp *ResponseCheckSpendProof, err error) {
-	err = c.do("check_spend_proof", &req, &resp)
+func (c *client) CheckSpendProof(req *RequestCheckSpendProof) (*ResponseCheckSpendProof, error) {
+	return c.CheckSpendProofContext(context.Background(), req)
+}
+
+func (c *client) CheckSpendProofContext(ctx context.Context, req *RequestCheckSpendProof) (resp *ResponseCheckSpendProof, err error) {
+	resp = &ResponseCheckSpendProof{}
+	err = c.do(ctx, "check_spend_proof", req, resp)
 	if err != nil {
 		return nil, err
 	}
 	return
 }
 
-func (c *client) GetReserveProof(req *RequestGetReserveProof) (resp *ResponseGetReserveProof, err error) {
-	err = c.do("get_reserve_proof", &req, &resp)
+func (c *client) GetReserveProof(req *RequestGetReserveProof) (*ResponseGetReserveProof, error) {
+	return c.GetReserveProofContext(context.Background(), req)
+}
+
+func (c *client) GetReserveProofContext(ctx context.Context, req *RequestGetReserveProof) (resp *ResponseGetReserveProof, err error) {
+	resp = &ResponseGetReserveProof{}
+	err = c.do(ctx, "get_reserve_proof", req, resp)
 	if err != nil {
 		return nil, err
 	}
 	return
 }
 
-func (c *client) CheckReserveProof(req *RequestCheckReserveProof) (resp *ResponseCheckReserveProof, err error) {
-	err = c.do("check_reserve_proof", &req, &resp)
+func (c *client) CheckReserveProof(req *RequestCheckReserveProof) (*ResponseCheckReserveProof, error) {
+	return c.CheckReserveProofContext(context.Background(), req)
+}
+
+func (c *client) CheckReserveProofContext(ctx context.Context, req *RequestCheckReserveProof) (resp *ResponseCheckReserveProof, err error) {
+	resp = &ResponseCheckReserveProof{}
+	err = c.do(ctx, "check_reserve_proof", req, resp)
 	if err != nil {
 		return nil, err
 	}
 	return
 }
 
-func (c *client) GetTransfers(req *RequestGetTransfers) (resp *ResponseGetTransfers, err error) {
-	err = c.do("get_transfers", &req, &resp)
+func (c *client) GetTransfers(req *RequestGetTransfers) (*ResponseGetTransfers, error) {
+	return c.GetTransfersContext(context.Background(), req)
+}
+
+func (c *client) GetTransfersContext(ctx context.Context, req *RequestGetTransfers) (resp *ResponseGetTransfers, err error) {
+	resp = &ResponseGetTransfers{}
+	err = c.do(ctx, "get_transfers", req, resp)
 	if err != nil {
 		return nil, err
 	}
 	return
 }
 
-func (c *client) GetTransferByTxID(req *RequestGetTransferByTxID) (resp *ResponseGetTransferByTxID, err error) {
-	err = c.do("get_transfer_by_txid", &req, &resp)
+func (c *client) StreamTransfers(req *RequestGetTransfers, fn func(*Transfer) error) error {
+	return c.StreamTransfersContext(context.Background(), req, fn)
+}
+
+func (c *client) StreamTransfersContext(ctx context.Context, req *RequestGetTransfers, fn func(*Transfer) error) error {
+	ctx = context.WithValue(ctx, streamKey{}, true)
+	return c.do(ctx, "get_transfers", req, &transferStream{fn: fn})
+}
+
+func (c *client) GetTransferByTxID(req *RequestGetTransferByTxID) (*ResponseGetTransferByTxID, error) {
+	return c.GetTransferByTxIDContext(context.Background(), req)
+}
+
+func (c *client) GetTransferByTxIDContext(ctx context.Context, req *RequestGetTransferByTxID) (resp *ResponseGetTransferByTxID, err error) {
+	resp = &ResponseGetTransferByTxID{}
+	err = c.do(ctx, "get_transfer_by_txid", req, resp)
 	if err != nil {
 		return nil, err
 	}
 	return
 }
 
-func (c *client) Sign(req *RequestSign) (resp *ResponseSign, err error) {
-	err = c.do("sign", &req, &resp)
+func (c *client) Sign(req *RequestSign) (*ResponseSign, error) {
+	return c.SignContext(context.Background(), req)
+}
+
+func (c *client) SignContext(ctx context.Context, req *RequestSign) (resp *ResponseSign, err error) {
+	resp = &ResponseSign{}
+	err = c.do(ctx, "sign", req, resp)
 	if err != nil {
 		return nil, err
 	}
 	return
 }
 
-func (c *client) Verify(req *RequestVerify) (resp *ResponseVerify, err error) {
-	err = c.do("verify", &req, &resp)
+func (c *client) Verify(req *RequestVerify) (*ResponseVerify, error) {
+	return c.VerifyContext(context.Background(), req)
+}
+
+func (c *client) VerifyContext(ctx context.Context, req *RequestVerify) (resp *ResponseVerify, err error) {
+	resp = &ResponseVerify{}
+	err = c.do(ctx, "verify", req, resp)
 	if err != nil {
 		return nil, err
 	}
 	return
 }
 
-func (c *client) ExportOutputs() (resp *ResponseExportOutputs, err error) {
-	err = c.do("export_outputs", nil, &resp)
+func (c *client) ExportOutputs() (*ResponseExportOutputs, error) {
+	return c.ExportOutputsContext(context.Background())
+}
+
+func (c *client) ExportOutputsContext(ctx context.Context) (resp *ResponseExportOutputs, err error) {
+	resp = &ResponseExportOutputs{}
+	err = c.do(ctx, "export_outputs", nil, resp)
 	if err != nil {
 		return nil, err
 	}
 	return
 }
 
-func (c *client) ImportOutputs(req *RequestImportOutputs) (resp *ResponseImportOutputs, err error) {
-	err = c.do("import_outputs", &req, &resp)
+func (c *client) ImportOutputs(req *RequestImportOutputs) (*ResponseImportOutputs, error) {
+	return c.ImportOutputsContext(context.Background(), req)
+}
+
+func (c *client) ImportOutputsContext(ctx context.Context, req *RequestImportOutputs) (resp *ResponseImportOutputs, err error) {
+	resp = &ResponseImportOutputs{}
+	err = c.do(ctx, "import_outputs", req, resp)
 	if err != nil {
 		return nil, err
 	}
 	return
 }
 
-func (c *client) ExportKeyImages() (resp *ResponseExportKeyImages, err error) {
-	err = c.do("export_key_images", nil, &resp)
+func (c *client) ExportKeyImages() (*ResponseExportKeyImages, error) {
+	return c.ExportKeyImagesContext(context.Background())
+}
+
+func (c *client) ExportKeyImagesContext(ctx context.Context) (resp *ResponseExportKeyImages, err error) {
+	resp = &ResponseExportKeyImages{}
+	err = c.do(ctx, "export_key_images", nil, resp)
 	if err != nil {
 		return nil, err
 	}
 	return
 }
 
-func (c *client) ImportKeyImages(req *RequestImportKeyImages) (resp *ResponseImportKeyImages, err error) {
-	err = c.do("import_key_images", &req, &resp)
+func (c *client) ImportKeyImages(req *RequestImportKeyImages) (*ResponseImportKeyImages, error) {
+	return c.ImportKeyImagesContext(context.Background(), req)
+}
+
+func (c *client) ImportKeyImagesContext(ctx context.Context, req *RequestImportKeyImages) (resp *ResponseImportKeyImages, err error) {
+	resp = &ResponseImportKeyImages{}
+	err = c.do(ctx, "import_key_images", req, resp)
 	if err != nil {
 		return nil, err
 	}
 	return
 }
 
-func (c *client) MakeURI(req *RequestMakeURI) (resp *ResponseMakeURI, err error) {
-	err = c.do("make_uri", &req, &resp)
+func (c *client) MakeURI(req *RequestMakeURI) (*ResponseMakeURI, error) {
+	return c.MakeURIContext(context.Background(), req)
+}
+
+func (c *client) MakeURIContext(ctx context.Context, req *RequestMakeURI) (resp *ResponseMakeURI, err error) {
+	resp = &ResponseMakeURI{}
+	err = c.do(ctx, "make_uri", req, resp)
 	if err != nil {
 		return nil, err
 	}
 	return
 }
 
-func (c *client) ParseURI(req *RequestParseURI) (resp *ResponseParseURI, err error) {
-	err = c.do("parse_uri", &req, &resp)
+func (c *client) ParseURI(req *RequestParseURI) (*ResponseParseURI, error) {
+	return c.ParseURIContext(context.Background(), req)
+}
+
+func (c *client) ParseURIContext(ctx context.Context, req *RequestParseURI) (resp *ResponseParseURI, err error) {
+	resp = &ResponseParseURI{}
+	err = c.do(ctx, "parse_uri", req, resp)
 	if err != nil {
 		return nil, err
 	}
 	return
 }
 
-func (c *client) GetAddressBook(req *RequestGetAddressBook) (resp *ResponseGetAddressBook, err error) {
-	err = c.do("get_address_book", &req, &resp)
+func (c *client) GetAddressBook(req *RequestGetAddressBook) (*ResponseGetAddressBook, error) {
+	return c.GetAddressBookContext(context.Background(), req)
+}
+
+func (c *client) GetAddressBookContext(ctx context.Context, req *RequestGetAddressBook) (resp *ResponseGetAddressBook, err error) {
+	resp = &ResponseGetAddressBook{}
+	err = c.do(ctx, "get_address_book", req, resp)
 	if err != nil {
 		return nil, err
 	}
 	return
 }
 
-func (c *client) AddAddressBook(req *RequestAddAddressBook) (resp *ResponseAddAddressBook, err error) {
-	err = c.do("add_address_book", &req, &resp)
+func (c *client) AddAddressBook(req *RequestAddAddressBook) (*ResponseAddAddressBook, error) {
+	return c.AddAddressBookContext(context.Background(), req)
+}
+
+func (c *client) AddAddressBookContext(ctx context.Context, req *RequestAddAddressBook) (resp *ResponseAddAddressBook, err error) {
+	resp = &ResponseAddAddressBook{}
+	err = c.do(ctx, "add_address_book", req, resp)
 	if err != nil {
 		return nil, err
 	}
 	return
 }
 
-func (c *client) DeleteAddressBook(req *RequestDeleteAddressBook) (err error) {
-	err = c.do("delete_address_book", &req, nil)
+func (c *client) DeleteAddressBook(req *RequestDeleteAddressBook) error {
+	return c.DeleteAddressBookContext(context.Background(), req)
+}
+
+func (c *client) DeleteAddressBookContext(ctx context.Context, req *RequestDeleteAddressBook) (err error) {
+	err = c.do(ctx, "delete_address_book", req, nil)
 	if err != nil {
 		return err
 	}
 	return
 }
-func (c *client) Refresh(req *RequestRefresh) (resp *ResponseRefresh, err error) {
-	err = c.do("refresh", &req, &resp)
+
+func (c *client) Refresh(req *RequestRefresh) (*ResponseRefresh, error) {
+	return c.RefreshContext(context.Background(), req)
+}
+
+func (c *client) RefreshContext(ctx context.Context, req *RequestRefresh) (resp *ResponseRefresh, err error) {
+	resp = &ResponseRefresh{}
+	err = c.do(ctx, "refresh", req, resp)
 	if err != nil {
 		return nil, err
 	}
 	return
 }
 
-func (c *client) RescanSpent() (err error) {
-	err = c.do("rescan_spent", nil, nil)
+func (c *client) AutoRefresh(req *RequestAutoRefresh) error {
+	return c.AutoRefreshContext(context.Background(), req)
+}
+
+func (c *client) AutoRefreshContext(ctx context.Context, req *RequestAutoRefresh) (err error) {
+	err = c.do(ctx, "auto_refresh", req, nil)
 	if err != nil {
 		return err
 	}
 	return
 }
-func (c *client) StartMining(req *RequestStartMining) (err error) {
-	err = c.do("start_mining", &req, nil)
+
+func (c *client) RescanSpent() error {
+	return c.RescanSpentContext(context.Background())
+}
+
+func (c *client) RescanSpentContext(ctx context.Context) (err error) {
+	err = c.do(ctx, "rescan_spent", nil, nil)
 	if err != nil {
 		return err
 	}
 	return
 }
-func (c *client) StopMining() (err error) {
-	err = c.do("stop_mining", nil, nil)
+
+func (c *client) StartMining(req *RequestStartMining) error {
+	return c.StartMiningContext(context.Background(), req)
+}
+
+func (c *client) StartMiningContext(ctx context.Context, req *RequestStartMining) (err error) {
+	err = c.do(ctx, "start_mining", req, nil)
+	if err != nil {
+		return err
+	}
+	return
+}
+
+func (c *client) StopMining() error {
+	return c.StopMiningContext(context.Background())
+}
+
+func (c *client) StopMiningContext(ctx context.Context) (err error) {
+	err = c.do(ctx, "stop_mining", nil, nil)
 	if err != nil {
 		return err
 	}
 	return
 }
-func (c *client) GetLanguages() (resp *ResponseGetLanguages, err error) {
-	err = c.do("get_languages", nil, &resp)
+
+func (c *client) GetLanguages() (*ResponseGetLanguages, error) {
+	return c.GetLanguagesContext(context.Background())
+}
+
+func (c *client) GetLanguagesContext(ctx context.Context) (resp *ResponseGetLanguages, err error) {
+	resp = &ResponseGetLanguages{}
+	err = c.do(ctx, "get_languages", nil, resp)
 	if err != nil {
 		return nil, err
 	}
 	return
 }
 
-func (c *client) CreateWallet(req *RequestCreateWallet) (err error) {
-	err = c.do("create_wallet", &req, nil)
+func (c *client) CreateWallet(req *RequestCreateWallet) error {
+	return c.CreateWalletContext(context.Background(), req)
+}
+
+func (c *client) CreateWalletContext(ctx context.Context, req *RequestCreateWallet) (err error) {
+	err = c.do(ctx, "create_wallet", req, nil)
 	if err != nil {
 		return err
 	}
 	return
 }
-func (c *client) GenerateFromKeys(req *RequestGenerateFromKeys) (resp *ResponseGenerateFromKeys, err error) {
-	err = c.do("generate_from_keys", &req, &resp)
+
+func (c *client) GenerateFromKeys(req *RequestGenerateFromKeys) (*ResponseGenerateFromKeys, error) {
+	return c.GenerateFromKeysContext(context.Background(), req)
+}
+
+func (c *client) GenerateFromKeysContext(ctx context.Context, req *RequestGenerateFromKeys) (resp *ResponseGenerateFromKeys, err error) {
+	resp = &ResponseGenerateFromKeys{}
+	err = c.do(ctx, "generate_from_keys", req, resp)
+	if err != nil {
+		return nil, err
+	}
+	return
+}
+
+func (c *client) RestoreDeterministicWallet(req *RequestRestoreDeterministicWallet) (*ResponseRestoreDeterministicWallet, error) {
+	return c.RestoreDeterministicWalletContext(context.Background(), req)
+}
+
+func (c *client) RestoreDeterministicWalletContext(ctx context.Context, req *RequestRestoreDeterministicWallet) (resp *ResponseRestoreDeterministicWallet, err error) {
+	resp = &ResponseRestoreDeterministicWallet{}
+	err = c.do(ctx, "restore_deterministic_wallet", req, resp)
 	if err != nil {
 		return nil, err
 	}
 	return
 }
-func (c *client) OpenWallet(req *RequestOpenWallet) (err error) {
-	err = c.do("open_wallet", &req, nil)
+
+func (c *client) OpenWallet(req *RequestOpenWallet) error {
+	return c.OpenWalletContext(context.Background(), req)
+}
+
+func (c *client) OpenWalletContext(ctx context.Context, req *RequestOpenWallet) (err error) {
+	err = c.do(ctx, "open_wallet", req, nil)
 	if err != nil {
 		return err
 	}
 	return
 }
-func (c *client) CloseWallet() (err error) {
-	err = c.do("close_wallet", nil, nil)
+
+func (c *client) CloseWallet() error {
+	return c.CloseWalletContext(context.Background())
+}
+
+func (c *client) CloseWalletContext(ctx context.Context) (err error) {
+	err = c.do(ctx, "close_wallet", nil, nil)
 	if err != nil {
 		return err
 	}
 	return
 }
-func (c *client) ChangeWalletPassword(req *RequestChangeWalletPassword) (err error) {
-	err = c.do("change_wallet_password", &req, nil)
+
+func (c *client) ChangeWalletPassword(req *RequestChangeWalletPassword) error {
+	return c.ChangeWalletPasswordContext(context.Background(), req)
+}
+
+func (c *client) ChangeWalletPasswordContext(ctx context.Context, req *RequestChangeWalletPassword) (err error) {
+	err = c.do(ctx, "change_wallet_password", req, nil)
 	if err != nil {
 		return err
 	}
 	return
 }
-func (c *client) IsMultisig() (resp *ResponseIsMultisig, err error) {
-	err = c.do("is_multisig", nil, &resp)
+
+func (c *client) IsMultisig() (*ResponseIsMultisig, error) {
+	return c.IsMultisigContext(context.Background())
+}
+
+func (c *client) IsMultisigContext(ctx context.Context) (resp *ResponseIsMultisig, err error) {
+	resp = &ResponseIsMultisig{}
+	err = c.do(ctx, "is_multisig", nil, resp)
 	if err != nil {
 		return nil, err
 	}
 	return
 }
 
-func (c *client) PrepareMultisig() (resp *ResponsePrepareMultisig, err error) {
-	err = c.do("prepare_multisig", nil, &resp)
+func (c *client) PrepareMultisig() (*ResponsePrepareMultisig, error) {
+	return c.PrepareMultisigContext(context.Background())
+}
+
+func (c *client) PrepareMultisigContext(ctx context.Context) (resp *ResponsePrepareMultisig, err error) {
+	resp = &ResponsePrepareMultisig{}
+	err = c.do(ctx, "prepare_multisig", nil, resp)
 	if err != nil {
 		return nil, err
 	}
 	return
 }
 
-func (c *client) MakeMultisig(req *RequestMakeMultisig) (resp *ResponseMakeMultisig, err error) {
-	err = c.do("make_multisig", &req, &resp)
+func (c *client) MakeMultisig(req *RequestMakeMultisig) (*ResponseMakeMultisig, error) {
+	return c.MakeMultisigContext(context.Background(), req)
+}
+
+func (c *client) MakeMultisigContext(ctx context.Context, req *RequestMakeMultisig) (resp *ResponseMakeMultisig, err error) {
+	resp = &ResponseMakeMultisig{}
+	err = c.do(ctx, "make_multisig", req, resp)
 	if err != nil {
 		return nil, err
 	}
 	return
 }
 
-func (c *client) ExportMultisigInfo() (resp *ResponseExportMultisigInfo, err error) {
-	err = c.do("export_multisig_info", nil, &resp)
+func (c *client) ExportMultisigInfo() (*ResponseExportMultisigInfo, error) {
+	return c.ExportMultisigInfoContext(context.Background())
+}
+
+func (c *client) ExportMultisigInfoContext(ctx context.Context) (resp *ResponseExportMultisigInfo, err error) {
+	resp = &ResponseExportMultisigInfo{}
+	err = c.do(ctx, "export_multisig_info", nil, resp)
 	if err != nil {
 		return nil, err
 	}
 	return
 }
 
-func (c *client) ImportMultisigInfo(req *RequestImportMultisigInfo) (resp *ResponseImportMultisigInfo, err error) {
-	err = c.do("import_multisig_info", &req, &resp)
+func (c *client) ImportMultisigInfo(req *RequestImportMultisigInfo) (*ResponseImportMultisigInfo, error) {
+	return c.ImportMultisigInfoContext(context.Background(), req)
+}
+
+func (c *client) ImportMultisigInfoContext(ctx context.Context, req *RequestImportMultisigInfo) (resp *ResponseImportMultisigInfo, err error) {
+	resp = &ResponseImportMultisigInfo{}
+	err = c.do(ctx, "import_multisig_info", req, resp)
 	if err != nil {
 		return nil, err
 	}
 	return
 }
 
-func (c *client) FinalizeMultisig(req *RequestFinalizeMultisig) (resp *ResponseFinalizeMultisig, err error) {
-	err = c.do("finalize_multisig", &req, &resp)
+func (c *client) FinalizeMultisig(req *RequestFinalizeMultisig) (*ResponseFinalizeMultisig, error) {
+	return c.FinalizeMultisigContext(context.Background(), req)
+}
+
+func (c *client) FinalizeMultisigContext(ctx context.Context, req *RequestFinalizeMultisig) (resp *ResponseFinalizeMultisig, err error) {
+	resp = &ResponseFinalizeMultisig{}
+	err = c.do(ctx, "finalize_multisig", req, resp)
 	if err != nil {
 		return nil, err
 	}
 	return
 }
 
-func (c *client) SignMultisig(req *RequestSignMultisig) (resp *ResponseSignMultisig, err error) {
-	err = c.do("sign_multisig", &req, &resp)
+func (c *client) SignMultisig(req *RequestSignMultisig) (*ResponseSignMultisig, error) {
+	return c.SignMultisigContext(context.Background(), req)
+}
+
+func (c *client) SignMultisigContext(ctx context.Context, req *RequestSignMultisig) (resp *ResponseSignMultisig, err error) {
+	resp = &ResponseSignMultisig{}
+	err = c.do(ctx, "sign_multisig", req, resp)
 	if err != nil {
 		return nil, err
 	}
 	return
 }
 
-func (c *client) SubmitMultisig(req *RequestSubmitMultisig) (resp *ResponseSubmitMultisig, err error) {
-	err = c.do("submit_multisig", &req, &resp)
+func (c *client) SubmitMultisig(req *RequestSubmitMultisig) (*ResponseSubmitMultisig, error) {
+	return c.SubmitMultisigContext(context.Background(), req)
+}
+
+func (c *client) SubmitMultisigContext(ctx context.Context, req *RequestSubmitMultisig) (resp *ResponseSubmitMultisig, err error) {
+	resp = &ResponseSubmitMultisig{}
+	err = c.do(ctx, "submit_multisig", req, resp)
 	if err != nil {
 		return nil, err
 	}
 	return
 }
 
-func (c *client) GetVersion() (resp *ResponseGetVersion, err error) {
-	err = c.do("get_version", nil, &resp)
+func (c *client) GetVersion() (*ResponseGetVersion, error) {
+	return c.GetVersionContext(context.Background())
+}
+
+func (c *client) GetVersionContext(ctx context.Context) (resp *ResponseGetVersion, err error) {
+	resp = &ResponseGetVersion{}
+	err = c.do(ctx, "get_version", nil, resp)
+	if err != nil {
+		return nil, err
+	}
+	return
+}
+
+func (c *client) Freeze(req *RequestFreeze) error {
+	return c.FreezeContext(context.Background(), req)
+}
+
+func (c *client) FreezeContext(ctx context.Context, req *RequestFreeze) (err error) {
+	err = c.do(ctx, "freeze", req, nil)
+	if err != nil {
+		return err
+	}
+	return
+}
+
+func (c *client) Thaw(req *RequestThaw) error {
+	return c.ThawContext(context.Background(), req)
+}
+
+func (c *client) ThawContext(ctx context.Context, req *RequestThaw) (err error) {
+	err = c.do(ctx, "thaw", req, nil)
+	if err != nil {
+		return err
+	}
+	return
+}
+
+func (c *client) Frozen(req *RequestFrozen) (*ResponseFrozen, error) {
+	return c.FrozenContext(context.Background(), req)
+}
+
+func (c *client) FrozenContext(ctx context.Context, req *RequestFrozen) (resp *ResponseFrozen, err error) {
+	resp = &ResponseFrozen{}
+	err = c.do(ctx, "frozen", req, resp)
+	if err != nil {
+		return nil, err
+	}
+	return
+}
+
+func (c *client) SetDaemon(req *RequestSetDaemon) error {
+	return c.SetDaemonContext(context.Background(), req)
+}
+
+func (c *client) SetDaemonContext(ctx context.Context, req *RequestSetDaemon) (err error) {
+	err = c.do(ctx, "set_daemon", req, nil)
+	if err != nil {
+		return err
+	}
+	return
+}
+
+func (c *client) EstimateTxSizeAndWeight(req *RequestEstimateTxSizeAndWeight) (*ResponseEstimateTxSizeAndWeight, error) {
+	return c.EstimateTxSizeAndWeightContext(context.Background(), req)
+}
+
+func (c *client) EstimateTxSizeAndWeightContext(ctx context.Context, req *RequestEstimateTxSizeAndWeight) (resp *ResponseEstimateTxSizeAndWeight, err error) {
+	resp = &ResponseEstimateTxSizeAndWeight{}
+	err = c.do(ctx, "estimate_tx_size_and_weight", req, resp)
+	if err != nil {
+		return nil, err
+	}
+	return
+}
+
+func (c *client) GetDefaultFeePriority() (*ResponseGetDefaultFeePriority, error) {
+	return c.GetDefaultFeePriorityContext(context.Background())
+}
+
+func (c *client) GetDefaultFeePriorityContext(ctx context.Context) (resp *ResponseGetDefaultFeePriority, err error) {
+	resp = &ResponseGetDefaultFeePriority{}
+	err = c.do(ctx, "get_default_fee_priority", nil, resp)
 	if err != nil {
 		return nil, err
 	}
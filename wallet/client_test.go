@@ -0,0 +1,156 @@
+package wallet
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type rpcRequest struct {
+	ID     json.RawMessage `json:"id"`
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params"`
+}
+
+// newTestServer starts a fake monero-wallet-rpc which answers every call
+// with the result returned by fn, or with the error if it is a *WalletError.
+func newTestServer(t *testing.T, fn func(req *rpcRequest) interface{}) *httptest.Server {
+	srv := httptest.NewServer(rpcHandler(fn))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// rpcHandler answers single JSON-RPC requests with the result of fn.
+func rpcHandler(fn func(req *rpcRequest) interface{}) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req := &rpcRequest{}
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		resp := H{"jsonrpc": "2.0", "id": req.ID}
+		if result := fn(req); isWalletError(result) {
+			resp["error"] = result
+		} else {
+			resp["result"] = result
+		}
+		json.NewEncoder(w).Encode(resp)
+	})
+}
+
+func isWalletError(v interface{}) bool {
+	werr, ok := v.(*WalletError)
+	return ok && werr != nil
+}
+
+func TestClientGetHeight(t *testing.T) {
+	srv := newTestServer(t, func(req *rpcRequest) interface{} {
+		assert.Equal(t, "get_height", req.Method)
+		return H{"height": 1234}
+	})
+	cl := New(Config{Address: srv.URL})
+
+	resp, err := cl.GetHeight()
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1234), resp.Height)
+}
+
+func TestClientContextCancel(t *testing.T) {
+	srv := newTestServer(t, func(req *rpcRequest) interface{} {
+		time.Sleep(200 * time.Millisecond)
+		return H{}
+	})
+	cl := NewContextClient(Config{Address: srv.URL})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err := cl.RefreshContext(ctx, &RequestRefresh{})
+	assert.Error(t, err)
+	assert.True(t, time.Since(start) < 200*time.Millisecond)
+	_, err = cl.GetHeightContext(ctx)
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+}
+
+func TestClientFreeze(t *testing.T) {
+	frozen := make(map[string]bool)
+	srv := newTestServer(t, func(req *rpcRequest) interface{} {
+		params := &RequestFrozen{}
+		json.Unmarshal(req.Params, params)
+		switch req.Method {
+		case "freeze":
+			frozen[params.KeyImage] = true
+		case "thaw":
+			delete(frozen, params.KeyImage)
+		case "frozen":
+			return H{"frozen": frozen[params.KeyImage]}
+		case "incoming_transfers":
+			return H{"transfers": []H{{"key_image": "ki1", "frozen": frozen["ki1"]}, {"key_image": "ki2"}}}
+		}
+		return H{}
+	})
+	cl := New(Config{Address: srv.URL})
+
+	assert.NoError(t, cl.Freeze(&RequestFreeze{KeyImage: "ki1"}))
+	resp, err := cl.Frozen(&RequestFrozen{KeyImage: "ki1"})
+	assert.NoError(t, err)
+	assert.True(t, resp.Frozen)
+
+	transfers, err := cl.IncomingTransfers(&RequestIncomingTransfers{TransferType: "available", Verbose: true})
+	assert.NoError(t, err)
+	assert.Len(t, transfers.Transfers, 2)
+	assert.True(t, transfers.Transfers[0].Frozen)
+	assert.False(t, transfers.Transfers[1].Frozen)
+
+	assert.NoError(t, cl.Thaw(&RequestThaw{KeyImage: "ki1"}))
+	resp, err = cl.Frozen(&RequestFrozen{KeyImage: "ki1"})
+	assert.NoError(t, err)
+	assert.False(t, resp.Frozen)
+}
+
+func TestClientRestoreDeterministicWallet(t *testing.T) {
+	srv := newTestServer(t, func(req *rpcRequest) interface{} {
+		assert.Equal(t, "restore_deterministic_wallet", req.Method)
+		assert.JSONEq(t, `{"restore_height":2000,"filename":"w","seed":"seed words","seed_offset":"offset",
+			"password":"p","language":"English","autosave_current":true}`, string(req.Params))
+		return H{"address": "4A...", "info": "Wallet has been restored successfully.", "seed": "seed words", "was_deprecated": false}
+	})
+	cl := New(Config{Address: srv.URL})
+
+	autosave := true
+	resp, err := cl.RestoreDeterministicWallet(&RequestRestoreDeterministicWallet{
+		RestoreHeight:   2000,
+		Filename:        "w",
+		Seed:            "seed words",
+		SeedOffset:      "offset",
+		Password:        "p",
+		Language:        "English",
+		AutoSaveCurrent: &autosave,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "4A...", resp.Address)
+	assert.Equal(t, "seed words", resp.Seed)
+
+	// without AutoSaveCurrent the server default applies
+	srv = newTestServer(t, func(req *rpcRequest) interface{} {
+		assert.NotContains(t, string(req.Params), "autosave_current")
+		return H{}
+	})
+	_, err = New(Config{Address: srv.URL}).RestoreDeterministicWallet(&RequestRestoreDeterministicWallet{Filename: "w"})
+	assert.NoError(t, err)
+}
+
+func TestClientRescanBlockchain(t *testing.T) {
+	srv := newTestServer(t, func(req *rpcRequest) interface{} {
+		assert.Equal(t, "rescan_blockchain", req.Method)
+		assert.JSONEq(t, `{"hard":true}`, string(req.Params))
+		return H{}
+	})
+	assert.NoError(t, New(Config{Address: srv.URL}).RescanBlockchain(&RequestRescanBlockchain{Hard: true}))
+}
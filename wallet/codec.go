@@ -0,0 +1,149 @@
+package wallet
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync/atomic"
+)
+
+// ErrNullResult is returned when a response has neither a result nor an error.
+var ErrNullResult = errors.New("result is null")
+
+// Codec configures how JSON-RPC 2.0 requests are encoded and responses decoded.
+type Codec struct {
+	// UseNumber decodes numbers into interface{} values (eg. in H maps) as
+	// json.Number instead of float64, so amounts above 2^53 keep their precision.
+	UseNumber bool
+	// Strict fails responses carrying fields the result struct lacks with an
+	// *UnknownFieldError, to notice additions of newer monero-wallet-rpc versions.
+	Strict bool
+	// NewID returns the id of a request, a string or a number. Defaults to
+	// a counter starting at 1.
+	NewID func() interface{}
+}
+
+// UnknownFieldError is returned by a strict Codec for a response field
+// the result struct lacks.
+type UnknownFieldError struct {
+	Field string
+}
+
+func (e *UnknownFieldError) Error() string {
+	return fmt.Sprintf("unknown field %q in response", e.Field)
+}
+
+// codec encodes and decodes JSON-RPC 2.0 messages.
+type codec struct {
+	useNumber bool
+	strict    bool
+	newID     func() interface{}
+}
+
+func newCodec(cfg *Codec) *codec {
+	c := &codec{}
+	if cfg != nil {
+		c.useNumber = cfg.UseNumber
+		c.strict = cfg.Strict
+		c.newID = cfg.NewID
+	}
+	if c.newID == nil {
+		var seq uint64
+		c.newID = func() interface{} {
+			return atomic.AddUint64(&seq, 1)
+		}
+	}
+	return c
+}
+
+type clientRequest struct {
+	Version string      `json:"jsonrpc"`
+	Method  string      `json:"method"`
+	Params  interface{} `json:"params"`
+	ID      interface{} `json:"id"`
+}
+
+type clientResponse struct {
+	ID     json.RawMessage `json:"id"`
+	Result json.RawMessage `json:"result"`
+	Error  json.RawMessage `json:"error"`
+}
+
+// request returns a request for method, along with its id encoded as JSON
+// to match the responses of batch requests.
+func (c *codec) request(method string, params interface{}) (*clientRequest, json.RawMessage, error) {
+	req := &clientRequest{
+		Version: "2.0",
+		Method:  method,
+		Params:  params,
+		ID:      c.newID(),
+	}
+	id, err := json.Marshal(req.ID)
+	return req, id, err
+}
+
+// call sends a single JSON-RPC call through s.
+func (c *codec) call(ctx context.Context, s sender, method string, in, out interface{}) error {
+	req, _, err := c.request(method, in)
+	if err != nil {
+		return err
+	}
+	payload, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+
+	return s.send(ctx, []string{method}, payload, func(body io.Reader) error {
+		if d, ok := out.(streamDecoder); ok {
+			return d.decodeResponse(body)
+		}
+		resp := &clientResponse{}
+		if err := json.NewDecoder(body).Decode(resp); err != nil {
+			return err
+		}
+		return c.result(resp, out)
+	})
+}
+
+// result decodes the result of resp into out, or returns its error.
+func (c *codec) result(resp *clientResponse, out interface{}) error {
+	if !isNull(resp.Error) {
+		werr := &WalletError{}
+		if err := json.Unmarshal(resp.Error, werr); err != nil {
+			return &WalletError{
+				Code:    ErrInternal,
+				Message: string(resp.Error),
+			}
+		}
+		return werr
+	}
+	if isNull(resp.Result) {
+		return ErrNullResult
+	}
+	// in theory this is only done to catch
+	// any monero related errors if
+	// we are not expecting any data back
+	if out == nil {
+		return nil
+	}
+	dec := json.NewDecoder(bytes.NewReader(resp.Result))
+	if c.useNumber {
+		dec.UseNumber()
+	}
+	if c.strict {
+		dec.DisallowUnknownFields()
+	}
+	err := dec.Decode(out)
+	if err != nil && c.strict && strings.HasPrefix(err.Error(), "json: unknown field ") {
+		return &UnknownFieldError{Field: strings.Trim(strings.TrimPrefix(err.Error(), "json: unknown field "), `"`)}
+	}
+	return err
+}
+
+func isNull(raw json.RawMessage) bool {
+	return len(raw) == 0 || bytes.Equal(raw, []byte("null"))
+}
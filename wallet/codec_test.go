@@ -0,0 +1,77 @@
+package wallet
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCodecUseNumber(t *testing.T) {
+	srv := newTestServer(t, func(req *rpcRequest) interface{} {
+		return json.RawMessage(`{"balance":18446744073709551615}`)
+	})
+	cl := NewContextClient(Config{Address: srv.URL, Codec: &Codec{UseNumber: true}})
+
+	elems := []*BatchElem{{Method: "get_balance", Result: &H{}}}
+	assert.NoError(t, cl.BatchCallContext(context.Background(), elems))
+	assert.NoError(t, elems[0].Error)
+	assert.Equal(t, json.Number("18446744073709551615"), (*elems[0].Result.(*H))["balance"])
+}
+
+func TestCodecStrict(t *testing.T) {
+	srv := newTestServer(t, func(req *rpcRequest) interface{} {
+		return H{"height": 1, "new_field": true}
+	})
+
+	resp, err := New(Config{Address: srv.URL}).GetHeight()
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1), resp.Height)
+
+	_, err = New(Config{Address: srv.URL, Codec: &Codec{Strict: true}}).GetHeight()
+	var ferr *UnknownFieldError
+	assert.True(t, errors.As(err, &ferr))
+	assert.Equal(t, "new_field", ferr.Field)
+}
+
+func TestCodecNewID(t *testing.T) {
+	var ids []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var reqs []rpcRequest
+		json.NewDecoder(r.Body).Decode(&reqs)
+		resps := []H{}
+		for i := len(reqs) - 1; i >= 0; i-- {
+			ids = append(ids, string(reqs[i].ID))
+			resps = append(resps, H{"jsonrpc": "2.0", "id": reqs[i].ID, "result": H{"height": i}})
+		}
+		json.NewEncoder(w).Encode(resps)
+	}))
+	defer srv.Close()
+	n := 0
+	cl := NewContextClient(Config{Address: srv.URL, Codec: &Codec{NewID: func() interface{} {
+		n++
+		return fmt.Sprintf("req-%d", n)
+	}}})
+
+	h1, h2 := &ResponseGetHeight{}, &ResponseGetHeight{}
+	elems := []*BatchElem{{Method: "get_height", Result: h1}, {Method: "get_height", Result: h2}}
+	assert.NoError(t, cl.BatchCallContext(context.Background(), elems))
+	assert.Equal(t, []string{`"req-2"`, `"req-1"`}, ids)
+	assert.Equal(t, uint64(0), h1.Height)
+	assert.Equal(t, uint64(1), h2.Height)
+}
+
+func TestCodecNullResult(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"jsonrpc":"2.0","id":1,"result":null}`)
+	}))
+	defer srv.Close()
+
+	_, err := New(Config{Address: srv.URL}).GetHeight()
+	assert.Equal(t, ErrNullResult, err)
+}
@@ -2,6 +2,7 @@ package wallet
 
 import (
 	"net/http"
+	"time"
 )
 
 // Config holds the configuration of a monero rpc client.
@@ -9,4 +10,41 @@ type Config struct {
 	Address       string
 	CustomHeaders map[string]string
 	Transport     http.RoundTripper
+	// Username and Password are the --rpc-login credentials. When Username is set,
+	// requests are authenticated with HTTP Digest authentication on top of Transport.
+	Username string
+	Password string
+	// Proxy is the URL of a SOCKS5 proxy to reach monero-wallet-rpc through,
+	// eg. "socks5h://127.0.0.1:9050" for Tor. With socks5h host names are
+	// resolved by the proxy, which is needed for .onion addresses; with socks5
+	// they are resolved locally. Credentials may be given in the URL.
+	// Proxy requires Transport to be nil or an *http.Transport.
+	Proxy string
+	// ProxyIsolation gives the client its own random proxy credentials, unless
+	// the Proxy URL has some. Tor routes streams with different credentials
+	// over separate circuits, so isolated clients do not share circuits.
+	ProxyIsolation bool
+	// TLS configures client certificates, CAs and certificate pinning for
+	// monero-wallet-rpc serving HTTPS. TLS requires Transport to be nil or
+	// an *http.Transport.
+	TLS *TLSConfig
+	// Timeout bounds every HTTP attempt of a call. Zero means no timeout.
+	Timeout time.Duration
+	// MethodTimeouts overrides Timeout for single JSON-RPC methods, keyed by
+	// method name (eg. "refresh" or "rescan_blockchain").
+	MethodTimeouts map[string]time.Duration
+	// MaxResponseSize fails calls with ErrResponseTooLarge once their response
+	// exceeds this many bytes, before it is fully read. Zero means no limit.
+	// Streamed responses, eg. of StreamTransfers, are not limited.
+	MaxResponseSize int64
+	// Retry enables retries of read-only calls. Nil disables retries.
+	// See ClassifyMethod for which calls are considered read-only.
+	Retry *RetryPolicy
+	// Scheduler queues calls, sending them by priority and at most
+	// MaxInFlight at a time. Nil sends calls as they come.
+	Scheduler *SchedulerConfig
+	// Codec configures the JSON-RPC encoding. Nil uses the defaults.
+	Codec *Codec
+	// Interceptors are run around every call, the first one being the outermost.
+	Interceptors []Interceptor
 }
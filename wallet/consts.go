@@ -1,16 +1,22 @@
 package wallet
 
 import (
+	"context"
+	"errors"
 	"fmt"
-
-	"github.com/gorilla/rpc/v2/json2"
+	"io"
+	"net"
+	"net/http"
 )
 
 // H is a helper map shortcut.
 type H map[string]interface{}
 
 // ErrorCode is a monero-wallet-rpc error code.
-// Copied from https://github.com/monero-project/monero/blob/release-v0.11.0.0/src/wallet/wallet_rpc_server_error_codes.h
+// Copied from https://github.com/monero-project/monero/blob/master/src/wallet/wallet_rpc_server_error_codes.h
+//
+// ErrorCode implements error, so the codes can be used as sentinel errors:
+// errors.Is(err, ErrNotEnoughMoney) is true if err is a *WalletError with that code.
 type ErrorCode int
 
 const (
@@ -36,12 +42,173 @@ const (
 	ErrWrongKeyImage ErrorCode = -10
 	// ErrWrongURI - E_WRONG_URI
 	ErrWrongURI ErrorCode = -11
-	// ErrWrongIndex - E_WRONG_INDEX
+	// ErrWrongIndex - E_WRONG_INDEX (also returned for an unknown address book entry)
 	ErrWrongIndex ErrorCode = -12
 	// ErrNotOpen - E_NOT_OPEN
 	ErrNotOpen ErrorCode = -13
+	// ErrAccountIndexOutOfBounds - E_ACCOUNT_INDEX_OUT_OF_BOUNDS
+	ErrAccountIndexOutOfBounds ErrorCode = -14
+	// ErrAddressIndexOutOfBounds - E_ADDRESS_INDEX_OUT_OF_BOUNDS
+	ErrAddressIndexOutOfBounds ErrorCode = -15
+	// ErrTxNotPossible - E_TX_NOT_POSSIBLE
+	ErrTxNotPossible ErrorCode = -16
+	// ErrNotEnoughMoney - E_NOT_ENOUGH_MONEY
+	ErrNotEnoughMoney ErrorCode = -17
+	// ErrTxTooLarge - E_TX_TOO_LARGE
+	ErrTxTooLarge ErrorCode = -18
+	// ErrNotEnoughOutsToMix - E_NOT_ENOUGH_OUTS_TO_MIX
+	ErrNotEnoughOutsToMix ErrorCode = -19
+	// ErrZeroDestination - E_ZERO_DESTINATION
+	ErrZeroDestination ErrorCode = -20
+	// ErrWalletAlreadyExists - E_WALLET_ALREADY_EXISTS
+	ErrWalletAlreadyExists ErrorCode = -21
+	// ErrInvalidPassword - E_INVALID_PASSWORD
+	ErrInvalidPassword ErrorCode = -22
+	// ErrNoWalletDir - E_NO_WALLET_DIR
+	ErrNoWalletDir ErrorCode = -23
+	// ErrNoTxKey - E_NO_TXKEY
+	ErrNoTxKey ErrorCode = -24
+	// ErrWrongKey - E_WRONG_KEY
+	ErrWrongKey ErrorCode = -25
+	// ErrBadHex - E_BAD_HEX
+	ErrBadHex ErrorCode = -26
+	// ErrBadTxMetadata - E_BAD_TX_METADATA
+	ErrBadTxMetadata ErrorCode = -27
+	// ErrAlreadyMultisig - E_ALREADY_MULTISIG
+	ErrAlreadyMultisig ErrorCode = -28
+	// ErrWatchOnly - E_WATCH_ONLY
+	ErrWatchOnly ErrorCode = -29
+	// ErrBadMultisigInfo - E_BAD_MULTISIG_INFO
+	ErrBadMultisigInfo ErrorCode = -30
+	// ErrNotMultisig - E_NOT_MULTISIG
+	ErrNotMultisig ErrorCode = -31
+	// ErrWrongLR - E_WRONG_LR
+	ErrWrongLR ErrorCode = -32
+	// ErrThresholdNotReached - E_THRESHOLD_NOT_REACHED
+	ErrThresholdNotReached ErrorCode = -33
+	// ErrBadMultisigTxData - E_BAD_MULTISIG_TX_DATA
+	ErrBadMultisigTxData ErrorCode = -34
+	// ErrMultisigSignature - E_MULTISIG_SIGNATURE
+	ErrMultisigSignature ErrorCode = -35
+	// ErrMultisigSubmission - E_MULTISIG_SUBMISSION
+	ErrMultisigSubmission ErrorCode = -36
+	// ErrNotEnoughUnlockedMoney - E_NOT_ENOUGH_UNLOCKED_MONEY
+	ErrNotEnoughUnlockedMoney ErrorCode = -37
+	// ErrNoDaemonConnection - E_NO_DAEMON_CONNECTION
+	ErrNoDaemonConnection ErrorCode = -38
+	// ErrBadUnsignedTxData - E_BAD_UNSIGNED_TX_DATA
+	ErrBadUnsignedTxData ErrorCode = -39
+	// ErrBadSignedTxData - E_BAD_SIGNED_TX_DATA
+	ErrBadSignedTxData ErrorCode = -40
+	// ErrSignedSubmission - E_SIGNED_SUBMISSION
+	ErrSignedSubmission ErrorCode = -41
+	// ErrSignUnsigned - E_SIGN_UNSIGNED
+	ErrSignUnsigned ErrorCode = -42
+	// ErrNonDeterministic - E_NON_DETERMINISTIC
+	ErrNonDeterministic ErrorCode = -43
+	// ErrInvalidLogLevel - E_INVALID_LOG_LEVEL
+	ErrInvalidLogLevel ErrorCode = -44
+	// ErrAttributeNotFound - E_ATTRIBUTE_NOT_FOUND
+	ErrAttributeNotFound ErrorCode = -45
+	// ErrZeroAmount - E_ZERO_AMOUNT
+	ErrZeroAmount ErrorCode = -46
+	// ErrInvalidSignatureType - E_INVALID_SIGNATURE_TYPE
+	ErrInvalidSignatureType ErrorCode = -47
+	// ErrDisabled - E_DISABLED
+	ErrDisabled ErrorCode = -48
+	// ErrProxyAlreadyDefined - E_PROXY_ALREADY_DEFINED
+	ErrProxyAlreadyDefined ErrorCode = -49
+	// ErrNonzeroUnlockTime - E_NONZERO_UNLOCK_TIME
+	ErrNonzeroUnlockTime ErrorCode = -50
+
+	// JSON-RPC 2.0 protocol errors.
+
+	// ErrParse - invalid JSON was received by the server
+	ErrParse ErrorCode = -32700
+	// ErrInvalidRequest - the JSON sent is not a valid request object
+	ErrInvalidRequest ErrorCode = -32600
+	// ErrMethodNotFound - the method does not exist or is not available
+	ErrMethodNotFound ErrorCode = -32601
+	// ErrInvalidParams - invalid method parameters
+	ErrInvalidParams ErrorCode = -32602
+	// ErrInternal - internal JSON-RPC error
+	ErrInternal ErrorCode = -32603
 )
 
+var errorCodeText = map[ErrorCode]string{
+	ErrUnknown:                 "unknown error",
+	ErrWrongAddress:            "wrong address",
+	ErrDaemonIsBusy:            "daemon is busy",
+	ErrGenericTransferError:    "generic transfer error",
+	ErrWrongPaymentID:          "wrong payment id",
+	ErrTransferType:            "wrong transfer type",
+	ErrDenied:                  "denied",
+	ErrWrongTxID:               "wrong txid",
+	ErrWrongSignature:          "wrong signature",
+	ErrWrongKeyImage:           "wrong key image",
+	ErrWrongURI:                "wrong uri",
+	ErrWrongIndex:              "wrong index",
+	ErrNotOpen:                 "no wallet open",
+	ErrAccountIndexOutOfBounds: "account index out of bounds",
+	ErrAddressIndexOutOfBounds: "address index out of bounds",
+	ErrTxNotPossible:           "transaction not possible",
+	ErrNotEnoughMoney:          "not enough money",
+	ErrTxTooLarge:              "transaction too large",
+	ErrNotEnoughOutsToMix:      "not enough outputs to mix",
+	ErrZeroDestination:         "zero destination",
+	ErrWalletAlreadyExists:     "wallet already exists",
+	ErrInvalidPassword:         "invalid password",
+	ErrNoWalletDir:             "no wallet dir",
+	ErrNoTxKey:                 "no tx key",
+	ErrWrongKey:                "wrong key",
+	ErrBadHex:                  "bad hex",
+	ErrBadTxMetadata:           "bad tx metadata",
+	ErrAlreadyMultisig:         "already multisig",
+	ErrWatchOnly:               "watch-only wallet",
+	ErrBadMultisigInfo:         "bad multisig info",
+	ErrNotMultisig:             "not multisig",
+	ErrWrongLR:                 "wrong LR",
+	ErrThresholdNotReached:     "threshold not reached",
+	ErrBadMultisigTxData:       "bad multisig tx data",
+	ErrMultisigSignature:       "multisig signature error",
+	ErrMultisigSubmission:      "multisig submission error",
+	ErrNotEnoughUnlockedMoney:  "not enough unlocked money",
+	ErrNoDaemonConnection:      "no daemon connection",
+	ErrBadUnsignedTxData:       "bad unsigned tx data",
+	ErrBadSignedTxData:         "bad signed tx data",
+	ErrSignedSubmission:        "signed submission error",
+	ErrSignUnsigned:            "sign unsigned error",
+	ErrNonDeterministic:        "non-deterministic wallet",
+	ErrInvalidLogLevel:         "invalid log level",
+	ErrAttributeNotFound:       "attribute not found",
+	ErrZeroAmount:              "zero amount",
+	ErrInvalidSignatureType:    "invalid signature type",
+	ErrDisabled:                "disabled",
+	ErrProxyAlreadyDefined:     "proxy already defined",
+	ErrNonzeroUnlockTime:       "nonzero unlock time",
+	ErrParse:                   "parse error",
+	ErrInvalidRequest:          "invalid request",
+	ErrMethodNotFound:          "method not found",
+	ErrInvalidParams:           "invalid params",
+	ErrInternal:                "internal error",
+}
+
+func (code ErrorCode) Error() string {
+	if text, ok := errorCodeText[code]; ok {
+		return text
+	}
+	return fmt.Sprintf("wallet-rpc error %d", int(code))
+}
+
+// Retryable tells whether a read-only call failing with this code may succeed when sent again.
+func (code ErrorCode) Retryable() bool {
+	return code == ErrDaemonIsBusy || code == ErrNoDaemonConnection
+}
+
+// ErrUnauthorized is matched by a *HTTPError with status 401 Unauthorized,
+// ie. when the --rpc-login credentials are missing or wrong.
+var ErrUnauthorized = errors.New("unauthorized")
+
 // WalletError is the error structured returned by the monero-wallet-rpc
 type WalletError struct {
 	Code    ErrorCode `json:"code"`
@@ -49,7 +216,62 @@ type WalletError struct {
 }
 
 func (we *WalletError) Error() string {
-	return fmt.Sprintf("%v: %v", we.Code, we.Message)
+	return fmt.Sprintf("%d: %v", int(we.Code), we.Message)
+}
+
+// Is reports whether target is the ErrorCode of we.
+func (we *WalletError) Is(target error) bool {
+	code, ok := target.(ErrorCode)
+	return ok && code == we.Code
+}
+
+// Retryable tells whether a read-only call failing with we may succeed when sent again.
+func (we *WalletError) Retryable() bool {
+	return we.Code.Retryable()
+}
+
+// HTTPError is returned when monero-wallet-rpc answers with a HTTP status other than 200 OK.
+type HTTPError struct {
+	StatusCode int
+	// Beginning of the response body.
+	Body []byte
+}
+
+func (he *HTTPError) Error() string {
+	return fmt.Sprintf("http status %v", he.StatusCode)
+}
+
+// Is reports whether target is ErrUnauthorized and the status is 401 Unauthorized.
+func (he *HTTPError) Is(target error) bool {
+	return target == ErrUnauthorized && he.StatusCode == http.StatusUnauthorized
+}
+
+// Retryable tells whether a read-only call failing with he may succeed when sent again.
+func (he *HTTPError) Retryable() bool {
+	switch he.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// IsRetryable tells whether a call failing with err may succeed when sent again:
+// network errors, timeouts of a single attempt, overloaded servers and a busy or
+// disconnected daemon. It does not look at the method, so it only says something
+// about read-only calls: spending calls must never be sent again automatically.
+func IsRetryable(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+	var r interface{ Retryable() bool }
+	if errors.As(err, &r) {
+		return r.Retryable()
+	}
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return true
+	}
+	var nerr net.Error
+	return errors.As(err, &nerr)
 }
 
 // GetWalletError checks if an erro interface is a wallet-rpc error.
@@ -57,27 +279,22 @@ func GetWalletError(err error) (isWalletError bool, werr *WalletError) {
 	if err == nil {
 		return false, nil
 	}
-	gerr, ok := err.(*json2.Error)
-	if !ok {
-		return false, nil
-	}
-	werr = &WalletError{
-		Code:    ErrorCode(gerr.Code),
-		Message: gerr.Message,
+	if errors.As(err, &werr) {
+		return true, werr
 	}
-	isWalletError = true
-	return
+	return false, nil
 }
 
 // Priority represents a transaction priority
 type Priority uint
 
-// Accepted Values are: 0-3 for: default, unimportant, normal, elevated, priority.
+// Accepted Values are: 0-4 for: default, unimportant, normal, elevated, priority.
 const (
 	PriorityDefault     Priority = 0
 	PriorityUnimportant Priority = 1
 	PriorityNormal      Priority = 2
 	PriorityElevated    Priority = 3
+	PriorityHighest     Priority = 4
 )
 
 // GetTransferType is a string that contains the possible types:
@@ -106,3 +323,15 @@ const (
 	// QueryKeySpend is the private spend key
 	QueryKeySpend QueryKeyType = "spend_key" //TODO: test
 )
+
+// SSLSupport is the parameter to send with client.SetDaemon()
+type SSLSupport string
+
+const (
+	// SSLDisabled connects to the daemon without SSL
+	SSLDisabled SSLSupport = "disabled"
+	// SSLEnabled requires SSL to connect to the daemon
+	SSLEnabled SSLSupport = "enabled"
+	// SSLAutodetect uses SSL if the daemon supports it
+	SSLAutodetect SSLSupport = "autodetect"
+)
@@ -0,0 +1,63 @@
+package wallet
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWalletErrorIs(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"jsonrpc":"2.0","id":0,"error":{"code":-17,"message":"not enough money"}}`)
+	}))
+	defer srv.Close()
+	cl := New(Config{Address: srv.URL})
+
+	_, err := cl.Transfer(&RequestTransfer{})
+	assert.True(t, errors.Is(err, ErrNotEnoughMoney))
+	assert.False(t, errors.Is(err, ErrNotEnoughUnlockedMoney))
+	assert.Equal(t, "-17: not enough money", err.Error())
+	var werr *WalletError
+	assert.True(t, errors.As(err, &werr))
+	assert.Equal(t, ErrNotEnoughMoney, werr.Code)
+	isWalletError, werr := GetWalletError(fmt.Errorf("wrapped: %w", err))
+	assert.True(t, isWalletError)
+	assert.Equal(t, ErrNotEnoughMoney, werr.Code)
+	assert.False(t, IsRetryable(err))
+
+	assert.Equal(t, "not enough money", ErrNotEnoughMoney.Error())
+	assert.Equal(t, "wallet-rpc error -1234", ErrorCode(-1234).Error())
+}
+
+func TestHTTPError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		fmt.Fprint(w, "Unauthorized Access")
+	}))
+	defer srv.Close()
+	cl := New(Config{Address: srv.URL})
+
+	_, err := cl.GetBalance(&RequestGetBalance{})
+	assert.True(t, errors.Is(err, ErrUnauthorized))
+	var herr *HTTPError
+	assert.True(t, errors.As(err, &herr))
+	assert.Equal(t, http.StatusUnauthorized, herr.StatusCode)
+	assert.Equal(t, "Unauthorized Access", string(herr.Body))
+	assert.False(t, IsRetryable(err))
+}
+
+func TestIsRetryable(t *testing.T) {
+	assert.False(t, IsRetryable(nil))
+	assert.True(t, IsRetryable(&WalletError{Code: ErrDaemonIsBusy}))
+	assert.True(t, IsRetryable(&WalletError{Code: ErrNoDaemonConnection}))
+	assert.False(t, IsRetryable(&WalletError{Code: ErrNotEnoughMoney}))
+	assert.True(t, IsRetryable(&HTTPError{StatusCode: http.StatusServiceUnavailable}))
+	assert.False(t, IsRetryable(&HTTPError{StatusCode: http.StatusNotFound}))
+	assert.True(t, IsRetryable(context.DeadlineExceeded))
+	assert.False(t, IsRetryable(context.Canceled))
+}
@@ -0,0 +1,94 @@
+package wallet
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrNoHealthyDaemon is returned by Failover when none of the daemons is healthy.
+var ErrNoHealthyDaemon = errors.New("no healthy daemon")
+
+// failoverBlockTime is twice the 2 minute block time of monero, so that a
+// daemon is not taken for lagging when blocks come slower than usual.
+const failoverBlockTime = 4 * time.Minute
+
+// FailoverConfig configures Failover.
+type FailoverConfig struct {
+	// Daemons are the candidate daemons, tried in order.
+	Daemons []*RequestSetDaemon
+	// CheckTimeout bounds switching to and checking a single daemon. Defaults to 1 minute.
+	CheckTimeout time.Duration
+	// Since is when the wallet was last synced, eg. the time of its last
+	// successful refresh. A daemon must provide the blocks mined since then.
+	// Defaults to the time Failover is called.
+	Since time.Time
+}
+
+// Failover moves the wallet of client to the first healthy daemon of
+// cfg.Daemons, without restarting monero-wallet-rpc, and returns it.
+//
+// Each daemon is set with SetDaemon and checked by refreshing the wallet from
+// the height it had before the switch. The daemon is healthy if the refresh
+// succeeds and GetHeight reports at least the height before the switch plus
+// one block per 4 minutes since cfg.Since, so a daemon lagging behind the
+// network is not kept. If no daemon is healthy, an error matching
+// ErrNoHealthyDaemon is returned and the wallet stays connected to the last
+// daemon tried.
+func Failover(ctx context.Context, client ContextClient, cfg FailoverConfig) (*RequestSetDaemon, error) {
+	timeout := cfg.CheckTimeout
+	if timeout <= 0 {
+		timeout = time.Minute
+	}
+	since := cfg.Since
+	if since.IsZero() {
+		since = time.Now()
+	}
+	before, err := client.GetHeightContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	errs := []error{ErrNoHealthyDaemon}
+	for _, daemon := range cfg.Daemons {
+		err := checkDaemon(ctx, client, daemon, before.Height, since, timeout)
+		if err == nil {
+			return daemon, nil
+		}
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		errs = append(errs, fmt.Errorf("daemon %v: %w", daemon.Address, err))
+	}
+	return nil, errors.Join(errs...)
+}
+
+func checkDaemon(ctx context.Context, client ContextClient, daemon *RequestSetDaemon, height uint64, since time.Time, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	if err := client.SetDaemonContext(ctx, daemon); err != nil {
+		return err
+	}
+	if _, err := client.RefreshContext(ctx, &RequestRefresh{StartHeight: height}); err != nil {
+		return err
+	}
+	after, err := client.GetHeightContext(ctx)
+	if err != nil {
+		return err
+	}
+	if after.Height < height {
+		return fmt.Errorf("wallet height dropped from %d to %d", height, after.Height)
+	}
+	// a daemon behind the wallet or the network fetches no blocks, so the
+	// height is compared to the blocks mined since the wallet was synced
+	var mined uint64
+	if elapsed := time.Since(since); elapsed > 0 {
+		mined = uint64(elapsed / failoverBlockTime)
+	}
+	if want := height + mined; after.Height < want {
+		return fmt.Errorf("daemon is lagging: wallet height %d, expected %d", after.Height, want)
+	}
+	return nil
+}
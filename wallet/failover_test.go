@@ -0,0 +1,89 @@
+package wallet
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newFailoverServer is a monero-wallet-rpc at height 100 whose refresh fails
+// while it is connected to a daemon marked down, and fetches the blocks up to
+// the tip of the daemon otherwise.
+func newFailoverServer(t *testing.T, down map[string]bool, tips map[string]uint64, daemons *[]*RequestSetDaemon) *httptest.Server {
+	var current string
+	height := uint64(100)
+	return newTestServer(t, func(req *rpcRequest) interface{} {
+		switch req.Method {
+		case "set_daemon":
+			daemon := &RequestSetDaemon{}
+			json.Unmarshal(req.Params, daemon)
+			*daemons = append(*daemons, daemon)
+			current = daemon.Address
+		case "refresh":
+			if down[current] {
+				return &WalletError{Code: ErrNoDaemonConnection, Message: "No connection to daemon"}
+			}
+			var fetched uint64
+			if tip := tips[current]; tip > height {
+				fetched = tip - height
+				height = tip
+			}
+			return H{"blocks_fetched": fetched}
+		case "get_height":
+			return H{"height": height}
+		}
+		return H{}
+	})
+}
+
+func TestFailover(t *testing.T) {
+	var daemons []*RequestSetDaemon
+	srv := newFailoverServer(t, map[string]bool{"node1:18081": true}, nil, &daemons)
+	cl := NewContextClient(Config{Address: srv.URL})
+
+	candidates := []*RequestSetDaemon{
+		{Address: "node1:18081", Trusted: true},
+		{Address: "node2:18089", SSLSupport: SSLEnabled, SSLAllowedFingerprints: []string{"AB:CD"}},
+	}
+	daemon, err := Failover(context.Background(), cl, FailoverConfig{Daemons: candidates})
+	assert.NoError(t, err)
+	assert.Equal(t, candidates[1], daemon)
+	assert.Equal(t, candidates, daemons)
+
+	daemons = nil
+	_, err = Failover(context.Background(), cl, FailoverConfig{Daemons: candidates[:1]})
+	assert.True(t, errors.Is(err, ErrNoHealthyDaemon))
+	assert.True(t, errors.Is(err, ErrNoDaemonConnection))
+	assert.Len(t, daemons, 1)
+}
+
+func TestFailoverLagging(t *testing.T) {
+	var daemons []*RequestSetDaemon
+	srv := newFailoverServer(t, nil, map[string]uint64{"node1:18081": 100, "node2:18089": 110}, &daemons)
+	cl := NewContextClient(Config{Address: srv.URL})
+
+	candidates := []*RequestSetDaemon{
+		{Address: "node1:18081"},
+		{Address: "node2:18089"},
+	}
+	// 5 blocks are expected in 20 minutes
+	daemon, err := Failover(context.Background(), cl, FailoverConfig{
+		Daemons: candidates,
+		Since:   time.Now().Add(-20 * time.Minute),
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, candidates[1], daemon)
+	assert.Len(t, daemons, 2)
+
+	// a daemon without new blocks is healthy if none are expected
+	daemons = nil
+	daemon, err = Failover(context.Background(), cl, FailoverConfig{Daemons: candidates})
+	assert.NoError(t, err)
+	assert.Equal(t, candidates[0], daemon)
+	assert.Len(t, daemons, 1)
+}
@@ -0,0 +1,106 @@
+package wallet
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// feeMultipliers are the fee multipliers of the priorities from
+// PriorityUnimportant to PriorityHighest, applied by monero-wallet-rpc to the
+// per byte base fee when the daemon returns no fee for every priority.
+var feeMultipliers = [...]uint64{1, 5, 25, 1000}
+
+// FeePreviewConfig configures PreviewFee.
+type FeePreviewConfig struct {
+	// Fees are the fees per byte of weight in atomic units of the priorities
+	// from PriorityUnimportant to PriorityHighest, as returned under "fees" by
+	// the get_fee_estimate call of monerod since the 2021 fee scaling.
+	Fees []uint64
+	// FeePerByte is the base fee per byte of weight in atomic units, as returned
+	// under "fee" by get_fee_estimate. Required if Fees is not set by the
+	// daemon; the fees of the priorities are then derived from it.
+	FeePerByte uint64
+	// QuantizationMask is the "quantization_mask" returned by get_fee_estimate.
+	// Fees are rounded up to a multiple of it.
+	QuantizationMask uint64
+	// Inputs is the number of inputs expected to fund the transaction. Defaults to 2.
+	Inputs uint64
+	// RingSize of the inputs. Defaults to the ring size of the current fork.
+	RingSize uint64
+}
+
+// FeePreview is the expected fee of a transaction.
+type FeePreview struct {
+	// Size of the transaction in bytes.
+	Size uint64
+	// Weight of the transaction, which the fee is based on.
+	Weight uint64
+	// Priority is the priority asked for, resolved with GetDefaultFeePriority
+	// if it was PriorityDefault.
+	Priority Priority
+	// Fee is the expected fee at Priority, in atomic units.
+	Fee uint64
+	// Fees are the expected fees of every priority from PriorityUnimportant
+	// to PriorityHighest, in atomic units.
+	Fees map[Priority]uint64
+}
+
+// PreviewFee returns the expected fee of a transaction to dests, without
+// building it. Unlike a Transfer with DoNotRelay, no outputs are selected or
+// locked, so the fee is an estimate: the actual number of inputs may differ
+// from cfg.Inputs. A change output is counted on top of dests.
+func PreviewFee(ctx context.Context, client ContextClient, dests []*Destination, priority Priority, cfg FeePreviewConfig) (*FeePreview, error) {
+	fees := cfg.Fees
+	if len(fees) != len(feeMultipliers) {
+		if cfg.FeePerByte == 0 {
+			return nil, errors.New("fee per byte not set")
+		}
+		fees = make([]uint64, len(feeMultipliers))
+		for i, mult := range feeMultipliers {
+			fees[i] = cfg.FeePerByte * mult
+		}
+	}
+	if priority > PriorityHighest {
+		return nil, fmt.Errorf("invalid priority %d", priority)
+	}
+	if cfg.Inputs == 0 {
+		cfg.Inputs = 2
+	}
+
+	if priority == PriorityDefault {
+		resp, err := client.GetDefaultFeePriorityContext(ctx)
+		if err != nil {
+			return nil, err
+		}
+		priority = resp.Priority
+		if priority == PriorityDefault || priority > PriorityHighest {
+			priority = PriorityUnimportant
+		}
+	}
+	estimate, err := client.EstimateTxSizeAndWeightContext(ctx, &RequestEstimateTxSizeAndWeight{
+		NInputs:  cfg.Inputs,
+		NOutputs: uint64(len(dests)) + 1,
+		RingSize: cfg.RingSize,
+		RCT:      true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	preview := &FeePreview{
+		Size:     estimate.Size,
+		Weight:   estimate.Weight,
+		Priority: priority,
+		Fees:     make(map[Priority]uint64, len(fees)),
+	}
+	for i, perByte := range fees {
+		fee := estimate.Weight * perByte
+		if mask := cfg.QuantizationMask; mask > 1 {
+			fee = (fee + mask - 1) / mask * mask
+		}
+		preview.Fees[PriorityUnimportant+Priority(i)] = fee
+	}
+	preview.Fee = preview.Fees[priority]
+	return preview, nil
+}
@@ -0,0 +1,59 @@
+package wallet
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPreviewFee(t *testing.T) {
+	srv := newTestServer(t, func(req *rpcRequest) interface{} {
+		switch req.Method {
+		case "get_default_fee_priority":
+			return H{"priority": 2}
+		case "estimate_tx_size_and_weight":
+			assert.JSONEq(t, `{"n_inputs":2,"n_outputs":3,"rct":true}`, string(req.Params))
+			return H{"size": 2000, "weight": 2500}
+		}
+		return H{}
+	})
+	cl := NewContextClient(Config{Address: srv.URL})
+	dests := []*Destination{{Address: "4abc", Amount: 1}, {Address: "4def", Amount: 2}}
+
+	preview, err := PreviewFee(context.Background(), cl, dests, PriorityDefault, FeePreviewConfig{FeePerByte: 20, QuantizationMask: 10000})
+	assert.NoError(t, err)
+	assert.Equal(t, &FeePreview{
+		Size:     2000,
+		Weight:   2500,
+		Priority: PriorityNormal,
+		Fee:      250000,
+		Fees: map[Priority]uint64{
+			PriorityUnimportant: 50000,
+			PriorityNormal:      250000,
+			PriorityElevated:    1250000,
+			PriorityHighest:     50000000,
+		},
+	}, preview)
+
+	preview, err = PreviewFee(context.Background(), cl, dests, PriorityHighest, FeePreviewConfig{FeePerByte: 20})
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(50000000), preview.Fee)
+
+	// the fees of the daemon take precedence over the base fee
+	preview, err = PreviewFee(context.Background(), cl, dests, PriorityElevated, FeePreviewConfig{
+		Fees:       []uint64{20, 80, 320, 4000},
+		FeePerByte: 1,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, map[Priority]uint64{
+		PriorityUnimportant: 50000,
+		PriorityNormal:      200000,
+		PriorityElevated:    800000,
+		PriorityHighest:     10000000,
+	}, preview.Fees)
+	assert.Equal(t, uint64(800000), preview.Fee)
+
+	_, err = PreviewFee(context.Background(), cl, dests, PriorityNormal, FeePreviewConfig{})
+	assert.Error(t, err)
+}
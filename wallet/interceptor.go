@@ -0,0 +1,43 @@
+package wallet
+
+import "context"
+
+// Invoker sends a JSON-RPC call. params is the request struct of the method
+// (eg. *RequestGetBalance) or nil, result is a pointer to the response struct
+// (eg. *ResponseGetBalance) or nil if the method returns no data.
+type Invoker func(ctx context.Context, method string, params, result interface{}) error
+
+// Interceptor is called around every call of a client with the JSON-RPC method
+// name and the typed params and result. It calls next to send the call on, and
+// may inspect or change params and result before and after, or return without
+// calling next at all (eg. to answer from a cache or deny a call).
+type Interceptor func(ctx context.Context, method string, params, result interface{}, next Invoker) error
+
+// ChainInterceptors returns an Interceptor running the given interceptors in order,
+// the first one being the outermost. It returns nil if no interceptors are given.
+func ChainInterceptors(interceptors ...Interceptor) Interceptor {
+	var chain []Interceptor
+	for _, ic := range interceptors {
+		if ic != nil {
+			chain = append(chain, ic)
+		}
+	}
+	switch len(chain) {
+	case 0:
+		return nil
+	case 1:
+		return chain[0]
+	}
+	return func(ctx context.Context, method string, params, result interface{}, next Invoker) error {
+		return chain[0](ctx, method, params, result, chainInvoker(chain[1:], next))
+	}
+}
+
+func chainInvoker(chain []Interceptor, final Invoker) Invoker {
+	if len(chain) == 0 {
+		return final
+	}
+	return func(ctx context.Context, method string, params, result interface{}) error {
+		return chain[0](ctx, method, params, result, chainInvoker(chain[1:], final))
+	}
+}
@@ -0,0 +1,111 @@
+package wallet
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInterceptors(t *testing.T) {
+	srv := newTestServer(t, func(req *rpcRequest) interface{} {
+		return H{"balance": 5}
+	})
+
+	var order []string
+	record := func(name string) Interceptor {
+		return func(ctx context.Context, method string, params, result interface{}, next Invoker) error {
+			order = append(order, name+">"+method)
+			err := next(ctx, method, params, result)
+			order = append(order, name+"<"+method)
+			return err
+		}
+	}
+	var seen *RequestGetBalance
+	var got *ResponseGetBalance
+	typed := func(ctx context.Context, method string, params, result interface{}, next Invoker) error {
+		seen = params.(*RequestGetBalance)
+		err := next(ctx, method, params, result)
+		got = result.(*ResponseGetBalance)
+		return err
+	}
+	cl := New(Config{
+		Address:      srv.URL,
+		Interceptors: []Interceptor{record("a"), record("b"), typed},
+	})
+
+	req := &RequestGetBalance{AccountIndex: 3}
+	resp, err := cl.GetBalance(req)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a>get_balance", "b>get_balance", "b<get_balance", "a<get_balance"}, order)
+	assert.Equal(t, req, seen)
+	assert.Equal(t, resp, got)
+	assert.Equal(t, uint64(5), got.Balance)
+}
+
+func TestInterceptorShortCircuit(t *testing.T) {
+	var calls int32
+	srv := newTestServer(t, func(req *rpcRequest) interface{} {
+		atomic.AddInt32(&calls, 1)
+		return H{}
+	})
+	errDenied := errors.New("denied by policy")
+	policy := func(ctx context.Context, method string, params, result interface{}, next Invoker) error {
+		if ClassifyMethod(method) == MethodSpending {
+			return errDenied
+		}
+		return next(ctx, method, params, result)
+	}
+	cl := New(Config{
+		Address:      srv.URL,
+		Interceptors: []Interceptor{policy},
+	})
+
+	_, err := cl.SweepAll(&RequestSweepAll{})
+	assert.Equal(t, errDenied, err)
+	assert.Equal(t, int32(0), calls)
+	_, err = cl.GetHeight()
+	assert.NoError(t, err)
+	assert.Equal(t, int32(1), calls)
+}
+
+func TestInterceptorBatch(t *testing.T) {
+	var calls int32
+	srv := newTestServer(t, func(req *rpcRequest) interface{} {
+		atomic.AddInt32(&calls, 1)
+		return H{"height": 9}
+	})
+	var mu sync.Mutex
+	var methods []string
+	cache := func(ctx context.Context, method string, params, result interface{}, next Invoker) error {
+		mu.Lock()
+		methods = append(methods, method)
+		mu.Unlock()
+		if method == "get_version" {
+			result.(*ResponseGetVersion).Version = 1
+			return nil
+		}
+		return next(ctx, method, params, result)
+	}
+	cl := NewContextClient(Config{
+		Address:      srv.URL,
+		Interceptors: []Interceptor{cache},
+	})
+
+	version := &ResponseGetVersion{}
+	height := &ResponseGetHeight{}
+	elems := []*BatchElem{
+		{Method: "get_version", Result: version},
+		{Method: "get_height", Result: height},
+	}
+	assert.NoError(t, cl.BatchCallContext(context.Background(), elems))
+	assert.NoError(t, elems[0].Error)
+	assert.NoError(t, elems[1].Error)
+	assert.Equal(t, uint64(1), version.Version)
+	assert.Equal(t, uint64(9), height.Height)
+	assert.ElementsMatch(t, []string{"get_version", "get_height"}, methods)
+	assert.Equal(t, int32(1), calls)
+}
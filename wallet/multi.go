@@ -0,0 +1,252 @@
+package wallet
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"time"
+)
+
+// MultiConfig holds the configuration of a client talking to several
+// monero-wallet-rpc instances which serve the same wallet.
+type MultiConfig struct {
+	// Endpoints are the monero-wallet-rpc instances. The first one is the primary:
+	// all calls which are not read-only (see ClassifyMethod) are pinned to it.
+	// The Interceptors of the endpoint configs are ignored.
+	Endpoints []Config
+	// HealthCheckInterval is how often the endpoints are checked with GetVersion
+	// and GetHeight. Defaults to 30s.
+	HealthCheckInterval time.Duration
+	// MaxHeightLag is how many blocks an endpoint's wallet may be behind the
+	// highest known wallet height and still count as fully synced.
+	MaxHeightLag uint64
+	// Interceptors are run around every call, before it is routed to an endpoint.
+	Interceptors []Interceptor
+	// Codec configures the JSON-RPC encoding. The Codecs of the endpoint configs are ignored.
+	Codec *Codec
+}
+
+// EndpointStatus is the last known health of an endpoint.
+type EndpointStatus struct {
+	Address string
+	Primary bool
+	Healthy bool
+	// Synced is true if the wallet height is within MaxHeightLag of the highest known height.
+	Synced bool
+	// Wallet height as reported by GetHeight.
+	Height uint64
+	// RPC version as reported by GetVersion.
+	Version uint64
+	// Round trip time of the last health check.
+	Latency     time.Duration
+	LastChecked time.Time
+	// Error of the last health check or failed call.
+	Err error
+}
+
+// MultiClient is a monero-wallet-rpc client spread over several endpoints.
+// Read-only calls go to the healthiest fully synced endpoint and fail over to
+// the next one on transport errors. All other calls go to the primary endpoint only.
+type MultiClient struct {
+	ContextClient
+	pool *pool
+}
+
+// NewMulti returns a new client for the given endpoints.
+func NewMulti(cfg MultiConfig) (*MultiClient, error) {
+	if len(cfg.Endpoints) == 0 {
+		return nil, errors.New("no endpoints configured")
+	}
+	p := &pool{
+		codec:    newCodec(cfg.Codec),
+		interval: cfg.HealthCheckInterval,
+		maxLag:   cfg.MaxHeightLag,
+		status:   make([]EndpointStatus, len(cfg.Endpoints)),
+	}
+	if p.interval <= 0 {
+		p.interval = 30 * time.Second
+	}
+	for i, epcfg := range cfg.Endpoints {
+		ep := newEndpoint(epcfg)
+		if ep.err != nil {
+			return nil, fmt.Errorf("endpoint %v: %w", epcfg.Address, ep.err)
+		}
+		p.endpoints = append(p.endpoints, ep)
+		p.status[i] = EndpointStatus{
+			Address: epcfg.Address,
+			Primary: i == 0,
+			Healthy: true,
+		}
+	}
+	return &MultiClient{
+		ContextClient: &client{
+			ep:        p,
+			codec:     p.codec,
+			intercept: ChainInterceptors(cfg.Interceptors...),
+			session:   newSessionLock(),
+		},
+		pool: p,
+	}, nil
+}
+
+// Status returns the last known health of all endpoints, primary first.
+func (m *MultiClient) Status() []EndpointStatus {
+	m.pool.mu.Lock()
+	defer m.pool.mu.Unlock()
+	return append([]EndpointStatus(nil), m.pool.status...)
+}
+
+// Primary returns a client sending every call to the primary endpoint, for
+// reads which must see what the primary did, eg. its pending transfers.
+// It shares the interceptors and sessions of m.
+func (m *MultiClient) Primary() ContextClient {
+	c := *m.ContextClient.(*client)
+	c.ep = m.pool.endpoints[0]
+	return &c
+}
+
+// CheckHealth checks all endpoints right away and waits for the result.
+func (m *MultiClient) CheckHealth(ctx context.Context) {
+	m.pool.check(ctx)
+}
+
+// pool routes calls to a set of endpoints.
+type pool struct {
+	endpoints []*endpoint
+	codec     *codec
+	interval  time.Duration
+	maxLag    uint64
+
+	mu       sync.Mutex
+	status   []EndpointStatus
+	checked  time.Time
+	checking bool
+}
+
+func (p *pool) send(ctx context.Context, methods []string, payload []byte, decode func(io.Reader) error) error {
+	p.maybeCheck()
+	if !readOnly(methods) {
+		return p.endpoints[0].send(ctx, methods, payload, decode)
+	}
+
+	var err error
+	for _, i := range p.ranked() {
+		err = p.endpoints[i].send(ctx, methods, payload, decode)
+		if err == nil || ctx.Err() != nil || !shouldFailover(err) {
+			return err
+		}
+		p.markDown(i, err)
+	}
+	return err
+}
+
+// shouldFailover tells whether a failed read-only call should be tried on another endpoint.
+func shouldFailover(err error) bool {
+	return errors.Is(err, ErrNotOpen) || IsRetryable(err)
+}
+
+// ranked returns the endpoint indexes in the order read-only calls should try them:
+// healthy and synced endpoints by latency, then healthy ones by height, then the others.
+func (p *pool) ranked() []int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	idx := make([]int, len(p.status))
+	for i := range idx {
+		idx[i] = i
+	}
+	rank := func(s EndpointStatus) int {
+		switch {
+		case s.Healthy && s.Synced:
+			return 0
+		case s.Healthy:
+			return 1
+		}
+		return 2
+	}
+	sort.SliceStable(idx, func(a, b int) bool {
+		sa, sb := p.status[idx[a]], p.status[idx[b]]
+		if rank(sa) != rank(sb) {
+			return rank(sa) < rank(sb)
+		}
+		if rank(sa) == 0 {
+			return sa.Latency < sb.Latency
+		}
+		return sa.Height > sb.Height
+	})
+	return idx
+}
+
+func (p *pool) markDown(i int, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.status[i].Healthy = false
+	p.status[i].Err = err
+}
+
+// maybeCheck starts a health check in the background if the last one is due.
+func (p *pool) maybeCheck() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.checking || time.Since(p.checked) < p.interval {
+		return
+	}
+	p.checking = true
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), p.interval)
+		defer cancel()
+		p.check(ctx)
+	}()
+}
+
+// check queries the version and height of all endpoints.
+func (p *pool) check(ctx context.Context) {
+	results := make([]EndpointStatus, len(p.endpoints))
+	var wg sync.WaitGroup
+	for i, ep := range p.endpoints {
+		wg.Add(1)
+		go func(i int, ep *endpoint) {
+			defer wg.Done()
+			results[i] = p.checkEndpoint(ctx, ep)
+		}(i, ep)
+	}
+	wg.Wait()
+
+	var top uint64
+	for _, r := range results {
+		if r.Healthy && r.Height > top {
+			top = r.Height
+		}
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for i, r := range results {
+		r.Address = p.status[i].Address
+		r.Primary = p.status[i].Primary
+		r.Synced = r.Healthy && r.Height+p.maxLag >= top
+		p.status[i] = r
+	}
+	p.checked = time.Now()
+	p.checking = false
+}
+
+func (p *pool) checkEndpoint(ctx context.Context, ep *endpoint) EndpointStatus {
+	s := EndpointStatus{LastChecked: time.Now()}
+	version := &ResponseGetVersion{}
+	if s.Err = p.codec.call(ctx, ep, "get_version", nil, version); s.Err != nil {
+		return s
+	}
+	height := &ResponseGetHeight{}
+	if s.Err = p.codec.call(ctx, ep, "get_height", nil, height); s.Err != nil {
+		return s
+	}
+	s.Healthy = true
+	s.Version = version.Version
+	s.Height = height.Height
+	s.Latency = time.Since(s.LastChecked) / 2
+	return s
+}
@@ -0,0 +1,80 @@
+package wallet
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newWalletServer starts a fake wallet-rpc at the given wallet height, counting
+// the calls of each method.
+func newWalletServer(t *testing.T, height uint64, calls map[string]*int32) string {
+	srv := newTestServer(t, func(req *rpcRequest) interface{} {
+		if n, ok := calls[req.Method]; ok {
+			atomic.AddInt32(n, 1)
+		}
+		switch req.Method {
+		case "get_version":
+			return H{"version": 65562}
+		case "get_height":
+			return H{"height": height}
+		case "get_balance":
+			return H{"balance": height}
+		}
+		return H{"tx_hash": "abc"}
+	})
+	return srv.URL
+}
+
+func TestMultiClientRouting(t *testing.T) {
+	var primaryTransfers, primaryBalances, secondaryTransfers, secondaryBalances int32
+	primary := newWalletServer(t, 90, map[string]*int32{"transfer": &primaryTransfers, "get_balance": &primaryBalances})
+	secondary := newWalletServer(t, 100, map[string]*int32{"transfer": &secondaryTransfers, "get_balance": &secondaryBalances})
+
+	cl, err := NewMulti(MultiConfig{
+		Endpoints:           []Config{{Address: primary}, {Address: secondary}},
+		HealthCheckInterval: time.Hour,
+	})
+	assert.NoError(t, err)
+	cl.CheckHealth(context.Background())
+
+	status := cl.Status()
+	assert.True(t, status[0].Primary)
+	assert.False(t, status[0].Synced)
+	assert.True(t, status[1].Synced)
+	assert.Equal(t, uint64(100), status[1].Height)
+
+	// read-only calls go to the synced endpoint
+	resp, err := cl.GetBalance(&RequestGetBalance{})
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(100), resp.Balance)
+	assert.Equal(t, int32(1), secondaryBalances)
+
+	// spending calls are pinned to the primary
+	_, err = cl.Transfer(&RequestTransfer{})
+	assert.NoError(t, err)
+	assert.Equal(t, int32(1), primaryTransfers)
+	assert.Equal(t, int32(0), secondaryTransfers)
+}
+
+func TestMultiClientFailover(t *testing.T) {
+	primary := newWalletServer(t, 100, nil)
+	cl, err := NewMulti(MultiConfig{
+		Endpoints:           []Config{{Address: "http://127.0.0.1:1"}, {Address: primary}},
+		HealthCheckInterval: time.Hour,
+	})
+	assert.NoError(t, err)
+
+	resp, err := cl.GetHeight()
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(100), resp.Height)
+	assert.False(t, cl.Status()[0].Healthy)
+	assert.Error(t, cl.Status()[0].Err)
+
+	// spending calls do not fail over
+	_, err = cl.Transfer(&RequestTransfer{})
+	assert.Error(t, err)
+}
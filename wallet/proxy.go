@@ -0,0 +1,108 @@
+package wallet
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/proxy"
+)
+
+// ProxyError is returned when a call could not be sent through the proxy of
+// Config.Proxy, either because the proxy is unreachable or because it could
+// not connect to monero-wallet-rpc (eg. an onion service being down).
+type ProxyError struct {
+	// Proxy is the proxy URL, with any password masked.
+	Proxy string
+	Err   error
+}
+
+func (e *ProxyError) Error() string {
+	return fmt.Sprintf("proxy %v: %v", e.Proxy, e.Err)
+}
+
+func (e *ProxyError) Unwrap() error {
+	return e.Err
+}
+
+// setProxy makes t dial through the SOCKS5 proxy of cfg.
+func setProxy(t *http.Transport, cfg Config) error {
+	u, err := url.Parse(cfg.Proxy)
+	if err != nil {
+		return fmt.Errorf("invalid proxy url: %v", err)
+	}
+	switch u.Scheme {
+	case "socks5", "socks5h":
+	default:
+		return fmt.Errorf("unsupported proxy scheme %q, expected socks5 or socks5h", u.Scheme)
+	}
+	remoteDNS := u.Scheme == "socks5h"
+	if !remoteDNS {
+		if addr, err := url.Parse(cfg.Address); err == nil && strings.HasSuffix(addr.Hostname(), ".onion") {
+			return errors.New("onion addresses can only be resolved by the proxy, use a socks5h proxy url")
+		}
+	}
+
+	var auth *proxy.Auth
+	if u.User != nil {
+		password, _ := u.User.Password()
+		auth = &proxy.Auth{User: u.User.Username(), Password: password}
+	} else if cfg.ProxyIsolation {
+		auth = &proxy.Auth{User: randomHex(), Password: randomHex()}
+	}
+	d, err := proxy.SOCKS5("tcp", u.Host, auth, proxy.Direct)
+	if err != nil {
+		return fmt.Errorf("invalid proxy url: %v", err)
+	}
+	dialer := d.(proxy.ContextDialer)
+	name := u.Redacted()
+
+	t.Proxy = nil
+	t.DialContext = func(ctx context.Context, network, addr string) (net.Conn, error) {
+		if !remoteDNS {
+			resolved, err := resolve(ctx, addr)
+			if err != nil {
+				return nil, err
+			}
+			addr = resolved
+		}
+		conn, err := dialer.DialContext(ctx, network, addr)
+		if err != nil {
+			return nil, &ProxyError{Proxy: name, Err: err}
+		}
+		return conn, nil
+	}
+	return nil
+}
+
+// resolve looks up the host of addr locally, for proxies which
+// are not asked to resolve host names themselves.
+func resolve(ctx context.Context, addr string) (string, error) {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return "", err
+	}
+	if net.ParseIP(host) != nil {
+		return addr, nil
+	}
+	ips, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return "", err
+	}
+	return net.JoinHostPort(ips[0].String(), port), nil
+}
+
+// randomHex returns 16 random bytes, hex encoded.
+func randomHex() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,204 @@
+package wallet
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// socksServer is a minimal SOCKS5 proxy connecting every request to target,
+// recording the requested addresses and credentials.
+type socksServer struct {
+	net.Listener
+	target string
+
+	mu    sync.Mutex
+	addrs []string
+	users []string
+}
+
+func newSocksServer(t *testing.T, target string) *socksServer {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &socksServer{Listener: l, target: target}
+	t.Cleanup(func() { l.Close() })
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+	return s
+}
+
+func (s *socksServer) serve(conn net.Conn) {
+	defer conn.Close()
+	buf := make([]byte, 256)
+	if _, err := io.ReadFull(conn, buf[:2]); err != nil {
+		return
+	}
+	methods := buf[:buf[1]]
+	if _, err := io.ReadFull(conn, methods); err != nil {
+		return
+	}
+	user := ""
+	if bytesContain(methods, 2) {
+		conn.Write([]byte{5, 2})
+		if _, err := io.ReadFull(conn, buf[:2]); err != nil {
+			return
+		}
+		name := make([]byte, buf[1])
+		io.ReadFull(conn, name)
+		io.ReadFull(conn, buf[:1])
+		io.ReadFull(conn, make([]byte, buf[0]))
+		user = string(name)
+		conn.Write([]byte{1, 0})
+	} else {
+		conn.Write([]byte{5, 0})
+	}
+
+	if _, err := io.ReadFull(conn, buf[:4]); err != nil {
+		return
+	}
+	var host string
+	switch buf[3] {
+	case 1:
+		io.ReadFull(conn, buf[:4])
+		host = net.IP(buf[:4]).String()
+	case 3:
+		io.ReadFull(conn, buf[:1])
+		name := make([]byte, buf[0])
+		io.ReadFull(conn, name)
+		host = string(name)
+	default:
+		return
+	}
+	io.ReadFull(conn, buf[:2])
+	port := binary.BigEndian.Uint16(buf[:2])
+
+	s.mu.Lock()
+	s.addrs = append(s.addrs, net.JoinHostPort(host, strconv.Itoa(int(port))))
+	s.users = append(s.users, user)
+	s.mu.Unlock()
+
+	upstream, err := net.Dial("tcp", s.target)
+	if err != nil {
+		conn.Write([]byte{5, 4, 0, 1, 0, 0, 0, 0, 0, 0})
+		return
+	}
+	defer upstream.Close()
+	conn.Write([]byte{5, 0, 0, 1, 0, 0, 0, 0, 0, 0})
+	go io.Copy(upstream, conn)
+	io.Copy(conn, upstream)
+}
+
+func bytesContain(b []byte, c byte) bool {
+	for _, x := range b {
+		if x == c {
+			return true
+		}
+	}
+	return false
+}
+
+func TestProxyRemoteDNS(t *testing.T) {
+	srv := newTestServer(t, func(req *rpcRequest) interface{} {
+		return H{"height": 42}
+	})
+	socks := newSocksServer(t, srv.Listener.Addr().String())
+
+	cl := New(Config{
+		Address: "http://abcdefghijklmnop.onion:18082/json_rpc",
+		Proxy:   "socks5h://" + socks.Addr().String(),
+	})
+	resp, err := cl.GetHeight()
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(42), resp.Height)
+	assert.Equal(t, []string{"abcdefghijklmnop.onion:18082"}, socks.addrs)
+}
+
+func TestProxyIsolation(t *testing.T) {
+	srv := newTestServer(t, func(req *rpcRequest) interface{} {
+		return H{"height": 42}
+	})
+	socks := newSocksServer(t, srv.Listener.Addr().String())
+
+	cfg := Config{
+		Address:        "http://wallet.onion/json_rpc",
+		Proxy:          "socks5h://" + socks.Addr().String(),
+		ProxyIsolation: true,
+	}
+	for i := 0; i < 2; i++ {
+		_, err := New(cfg).GetHeight()
+		assert.NoError(t, err)
+	}
+	cfg.Proxy = "socks5h://alice:secret@" + socks.Addr().String()
+	_, err := New(cfg).GetHeight()
+	assert.NoError(t, err)
+
+	assert.Len(t, socks.users, 3)
+	assert.NotEmpty(t, socks.users[0])
+	assert.NotEqual(t, socks.users[0], socks.users[1])
+	assert.Equal(t, "alice", socks.users[2])
+}
+
+func TestProxyError(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := l.Addr().String()
+	l.Close()
+
+	cl := New(Config{
+		Address: "http://wallet.onion/json_rpc",
+		Proxy:   "socks5h://user:secret@" + addr,
+	})
+	_, err = cl.GetHeight()
+	var perr *ProxyError
+	assert.True(t, errors.As(err, &perr))
+	assert.Equal(t, "socks5h://user:xxxxx@"+addr, perr.Proxy)
+	isWalletError, _ := GetWalletError(err)
+	assert.False(t, isWalletError)
+
+	// the proxy is reachable, but not the onion service
+	socks := newSocksServer(t, addr)
+	cl = New(Config{
+		Address: "http://wallet.onion/json_rpc",
+		Proxy:   "socks5h://" + socks.Addr().String(),
+	})
+	_, err = cl.GetHeight()
+	assert.True(t, errors.As(err, &perr))
+}
+
+func TestProxyConfigErrors(t *testing.T) {
+	for _, cfg := range []Config{
+		{Address: "http://wallet.onion/json_rpc", Proxy: "socks5://127.0.0.1:9050"},
+		{Address: "http://127.0.0.1/json_rpc", Proxy: "ftp://127.0.0.1:9050"},
+		{Address: "http://127.0.0.1/json_rpc", Proxy: "socks5h://127.0.0.1:9050", Transport: roundTripFunc(nil)},
+	} {
+		_, err := New(cfg).GetHeight()
+		assert.Error(t, err)
+
+		_, err = NewMulti(MultiConfig{Endpoints: []Config{cfg}})
+		assert.Error(t, err)
+	}
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
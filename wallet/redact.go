@@ -0,0 +1,104 @@
+package wallet
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+)
+
+// Redacted replaces secret values in the output of Redact.
+const Redacted = "[REDACTED]"
+
+// secretNames are parts of field names which mark a field as secret
+// even without a redact tag.
+var secretNames = []string{"Password", "Passphrase", "Seed", "Mnemonic", "SpendKey", "ViewKey", "SecretKey", "TxKey"}
+
+// Redact returns a copy of a request or response struct which is safe to log.
+// Structs are turned into maps keyed by their JSON field names. Fields tagged
+// with `redact:"true"` are replaced by Redacted, as are untagged fields whose
+// name hints at a secret (eg. Password, Seed or SpendKey), so new secret fields
+// are masked even before they are tagged. A `redact:"false"` tag opts out of
+// the name check.
+func Redact(v interface{}) interface{} {
+	return redactValue(reflect.ValueOf(v))
+}
+
+func redactValue(v reflect.Value) interface{} {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	switch v.Kind() {
+	case reflect.Struct:
+		out := make(map[string]interface{}, v.NumField())
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if f.PkgPath != "" {
+				continue
+			}
+			name := jsonName(f)
+			if name == "-" {
+				continue
+			}
+			if isSecret(f) {
+				out[name] = Redacted
+				continue
+			}
+			out[name] = redactValue(v.Field(i))
+		}
+		return out
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			return nil
+		}
+		out := make([]interface{}, v.Len())
+		for i := range out {
+			out[i] = redactValue(v.Index(i))
+		}
+		return out
+	case reflect.Map:
+		if v.IsNil() {
+			return nil
+		}
+		out := make(map[string]interface{}, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			k, ok := iter.Key().Interface().(string)
+			if !ok {
+				b, _ := json.Marshal(iter.Key().Interface())
+				k = string(b)
+			}
+			out[k] = redactValue(iter.Value())
+		}
+		return out
+	case reflect.Invalid:
+		return nil
+	}
+	return v.Interface()
+}
+
+func isSecret(f reflect.StructField) bool {
+	switch f.Tag.Get("redact") {
+	case "true":
+		return true
+	case "false":
+		return false
+	}
+	for _, s := range secretNames {
+		if strings.Contains(f.Name, s) {
+			return true
+		}
+	}
+	return false
+}
+
+func jsonName(f reflect.StructField) string {
+	name := strings.Split(f.Tag.Get("json"), ",")[0]
+	if name == "" {
+		return f.Name
+	}
+	return name
+}
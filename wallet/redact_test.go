@@ -0,0 +1,57 @@
+package wallet
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedact(t *testing.T) {
+	assert.Equal(t, map[string]interface{}{
+		"restore_height":   int64(10),
+		"filename":         "w",
+		"address":          "4A...",
+		"spendkey":         Redacted,
+		"viewkey":          Redacted,
+		"password":         Redacted,
+		"autosave_current": false,
+		"language":         "",
+	}, Redact(&RequestGenerateFromKeys{
+		RestoreHeight: 10,
+		Filename:      "w",
+		Address:       "4A...",
+		SpendKey:      "s",
+		ViewKey:       "v",
+		Password:      "p",
+	}))
+
+	assert.Equal(t, map[string]interface{}{"key": Redacted}, Redact(&ResponseQueryKey{Key: "seed words"}))
+	assert.Equal(t, map[string]interface{}{
+		"address":        "4A...",
+		"info":           "Wallet has been restored successfully.",
+		"seed":           Redacted,
+		"was_deprecated": false,
+	}, Redact(&ResponseRestoreDeterministicWallet{
+		Address: "4A...",
+		Info:    "Wallet has been restored successfully.",
+		Seed:    "seed words",
+	}))
+	assert.Nil(t, Redact((*RequestOpenWallet)(nil)))
+	assert.Nil(t, Redact(nil))
+
+	// untagged fields are masked by name, nested structs are walked
+	type inner struct {
+		WalletPassword string `json:"wallet_password"`
+		Label          string `json:"label"`
+	}
+	type outer struct {
+		Entries  []inner `json:"entries"`
+		GetTxKey bool    `json:"get_tx_key" redact:"false"`
+	}
+	assert.Equal(t, map[string]interface{}{
+		"entries": []interface{}{
+			map[string]interface{}{"wallet_password": Redacted, "label": "a"},
+		},
+		"get_tx_key": true,
+	}, Redact(outer{Entries: []inner{{WalletPassword: "x", Label: "a"}}, GetTxKey: true}))
+}
@@ -0,0 +1,61 @@
+package wallet
+
+import (
+	"context"
+)
+
+// RefreshProgress reports the progress of RefreshInSteps.
+type RefreshProgress struct {
+	// Step counts the refresh calls made so far.
+	Step int
+	// StartHeight is the height the refresh started from.
+	StartHeight uint64
+	// Height is the wallet height after the last step.
+	Height uint64
+	// BlocksFetched is the number of blocks scanned by all steps so far.
+	BlocksFetched uint64
+	// ReceivedMoney is set once a step found transactions to the wallet.
+	ReceivedMoney bool
+}
+
+// RefreshInSteps refreshes the wallet of client from startHeight until it is
+// synced, calling fn with the progress after every step. It suits wallets
+// restored with an old restore height, eg. by GenerateFromKeys, whose refresh
+// may take hours.
+//
+// Each step is a Refresh call scanning up to the daemon height, followed by
+// GetHeight. Blocks mined meanwhile are scanned by the next step, and the
+// refresh is done once a step fetches no blocks.
+//
+// monero-wallet-rpc answers one call at a time, so the wallet height cannot be
+// polled while a Refresh call runs, and a step is not reported before it is
+// done. A wallet restored long ago may thus report its first step only once
+// most blocks were scanned.
+func RefreshInSteps(ctx context.Context, client ContextClient, startHeight uint64, fn func(RefreshProgress)) (*RefreshProgress, error) {
+	progress := &RefreshProgress{
+		StartHeight: startHeight,
+	}
+	req := &RequestRefresh{StartHeight: startHeight}
+	for {
+		resp, err := client.RefreshContext(ctx, req)
+		if err != nil {
+			return progress, err
+		}
+		height, err := client.GetHeightContext(ctx)
+		if err != nil {
+			return progress, err
+		}
+		progress.Step++
+		progress.Height = height.Height
+		progress.BlocksFetched += resp.BlocksFetched
+		progress.ReceivedMoney = progress.ReceivedMoney || resp.ReceivedMoney
+		if fn != nil {
+			fn(*progress)
+		}
+		if resp.BlocksFetched == 0 {
+			return progress, nil
+		}
+		// later steps continue from the wallet height
+		req = &RequestRefresh{}
+	}
+}
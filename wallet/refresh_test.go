@@ -0,0 +1,41 @@
+package wallet
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRefreshInSteps(t *testing.T) {
+	var starts []string
+	fetched := []uint64{1000, 3, 0}
+	height := uint64(500)
+	srv := newTestServer(t, func(req *rpcRequest) interface{} {
+		switch req.Method {
+		case "refresh":
+			starts = append(starts, string(req.Params))
+			n := fetched[0]
+			fetched = fetched[1:]
+			height += n
+			return H{"blocks_fetched": n, "received_money": n == 3}
+		case "get_height":
+			return H{"height": height}
+		}
+		return H{}
+	})
+	cl := NewContextClient(Config{Address: srv.URL})
+
+	var steps []RefreshProgress
+	progress, err := RefreshInSteps(context.Background(), cl, 500, func(p RefreshProgress) {
+		steps = append(steps, p)
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{`{"start_height":500}`, `{}`, `{}`}, starts)
+	assert.Equal(t, []RefreshProgress{
+		{Step: 1, StartHeight: 500, Height: 1500, BlocksFetched: 1000},
+		{Step: 2, StartHeight: 500, Height: 1503, BlocksFetched: 1003, ReceivedMoney: true},
+		{Step: 3, StartHeight: 500, Height: 1503, BlocksFetched: 1003, ReceivedMoney: true},
+	}, steps)
+	assert.Equal(t, &steps[2], progress)
+}
@@ -0,0 +1,158 @@
+package wallet
+
+import (
+	"context"
+	"math/rand"
+	"time"
+)
+
+// MethodClass tells whether a monero-wallet-rpc method may be sent again
+// after a failed attempt.
+type MethodClass int
+
+const (
+	// MethodReadOnly methods only read wallet state. They are safe to retry.
+	MethodReadOnly MethodClass = iota
+	// MethodMutating methods change wallet or server state (eg. create_address,
+	// open_wallet). They are never retried automatically.
+	MethodMutating
+	// MethodSpending methods may create or broadcast a transaction. They are
+	// never retried automatically, since a resent call may spend twice.
+	MethodSpending
+)
+
+var methodClasses = map[string]MethodClass{
+	"get_balance":                 MethodReadOnly,
+	"get_address":                 MethodReadOnly,
+	"get_address_index":           MethodReadOnly,
+	"validate_address":            MethodReadOnly,
+	"get_accounts":                MethodReadOnly,
+	"get_account_tags":            MethodReadOnly,
+	"get_height":                  MethodReadOnly,
+	"get_payments":                MethodReadOnly,
+	"get_bulk_payments":           MethodReadOnly,
+	"incoming_transfers":          MethodReadOnly,
+	"query_key":                   MethodReadOnly,
+	"make_integrated_address":     MethodReadOnly,
+	"split_integrated_address":    MethodReadOnly,
+	"get_tx_notes":                MethodReadOnly,
+	"get_attribute":               MethodReadOnly,
+	"get_tx_key":                  MethodReadOnly,
+	"check_tx_key":                MethodReadOnly,
+	"get_tx_proof":                MethodReadOnly,
+	"check_tx_proof":              MethodReadOnly,
+	"get_spend_proof":             MethodReadOnly,
+	"check_spend_proof":           MethodReadOnly,
+	"get_reserve_proof":           MethodReadOnly,
+	"check_reserve_proof":         MethodReadOnly,
+	"get_transfers":               MethodReadOnly,
+	"get_transfer_by_txid":        MethodReadOnly,
+	"sign":                        MethodReadOnly,
+	"verify":                      MethodReadOnly,
+	"export_outputs":              MethodReadOnly,
+	"export_key_images":           MethodReadOnly,
+	"make_uri":                    MethodReadOnly,
+	"parse_uri":                   MethodReadOnly,
+	"get_address_book":            MethodReadOnly,
+	"get_languages":               MethodReadOnly,
+	"is_multisig":                 MethodReadOnly,
+	"export_multisig_info":        MethodReadOnly,
+	"get_version":                 MethodReadOnly,
+	"frozen":                      MethodReadOnly,
+	"estimate_tx_size_and_weight": MethodReadOnly,
+	"get_default_fee_priority":    MethodReadOnly,
+
+	"transfer":        MethodSpending,
+	"transfer_split":  MethodSpending,
+	"submit_transfer": MethodSpending,
+	"sweep_dust":      MethodSpending,
+	"sweep_all":       MethodSpending,
+	"sweep_single":    MethodSpending,
+	"relay_tx":        MethodSpending,
+	"submit_multisig": MethodSpending,
+}
+
+// ClassifyMethod returns the class of a JSON-RPC method name (eg. "get_balance").
+// Unknown methods are reported as MethodMutating.
+func ClassifyMethod(method string) MethodClass {
+	if class, ok := methodClasses[method]; ok {
+		return class
+	}
+	return MethodMutating
+}
+
+// readOnly tells whether all of the methods are read-only.
+func readOnly(methods []string) bool {
+	for _, method := range methods {
+		if ClassifyMethod(method) != MethodReadOnly {
+			return false
+		}
+	}
+	return true
+}
+
+// RetryPolicy configures how failed read-only calls are retried.
+// Mutating and spending calls are sent exactly once, whatever the policy says.
+type RetryPolicy struct {
+	// Total number of attempts, including the first one. Values below 2 disable retries.
+	MaxAttempts int
+	// Delay before the first retry.
+	InitialBackoff time.Duration
+	// Upper bound of the delay between two attempts.
+	MaxBackoff time.Duration
+	// Factor the delay grows by after each attempt. Defaults to 2.
+	Multiplier float64
+	// Fraction of the delay, between 0 and 1, which is randomized to spread out
+	// retries of concurrent callers. A jitter of 0.5 waits between 50% and 100% of the delay.
+	Jitter float64
+}
+
+// DefaultRetryPolicy returns a policy of 4 attempts with exponential backoff
+// starting at 200ms, capped at 5s, with 50% jitter.
+func DefaultRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts:    4,
+		InitialBackoff: 200 * time.Millisecond,
+		MaxBackoff:     5 * time.Second,
+		Multiplier:     2,
+		Jitter:         0.5,
+	}
+}
+
+// backoff returns the delay to wait after the given (1-based) failed attempt.
+func (p *RetryPolicy) backoff(attempt int) time.Duration {
+	mult := p.Multiplier
+	if mult <= 0 {
+		mult = 2
+	}
+	d := float64(p.InitialBackoff)
+	for i := 1; i < attempt; i++ {
+		d *= mult
+		if p.MaxBackoff > 0 && d > float64(p.MaxBackoff) {
+			break
+		}
+	}
+	if p.MaxBackoff > 0 && d > float64(p.MaxBackoff) {
+		d = float64(p.MaxBackoff)
+	}
+	if p.Jitter > 0 {
+		j := p.Jitter
+		if j > 1 {
+			j = 1
+		}
+		d -= d * j * rand.Float64()
+	}
+	return time.Duration(d)
+}
+
+// sleepContext waits for d or until ctx is done.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
@@ -0,0 +1,85 @@
+package wallet
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClassifyMethod(t *testing.T) {
+	assert.Equal(t, MethodReadOnly, ClassifyMethod("get_balance"))
+	assert.Equal(t, MethodReadOnly, ClassifyMethod("get_transfers"))
+	assert.Equal(t, MethodReadOnly, ClassifyMethod("get_height"))
+	assert.Equal(t, MethodSpending, ClassifyMethod("transfer"))
+	assert.Equal(t, MethodSpending, ClassifyMethod("sweep_all"))
+	assert.Equal(t, MethodSpending, ClassifyMethod("relay_tx"))
+	assert.Equal(t, MethodSpending, ClassifyMethod("submit_transfer"))
+	assert.Equal(t, MethodMutating, ClassifyMethod("open_wallet"))
+	assert.Equal(t, MethodMutating, ClassifyMethod("some_future_method"))
+}
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	p := &RetryPolicy{InitialBackoff: 100 * time.Millisecond, MaxBackoff: 300 * time.Millisecond}
+	assert.Equal(t, 100*time.Millisecond, p.backoff(1))
+	assert.Equal(t, 200*time.Millisecond, p.backoff(2))
+	assert.Equal(t, 300*time.Millisecond, p.backoff(3))
+	assert.Equal(t, 300*time.Millisecond, p.backoff(10))
+
+	p.Jitter = 0.5
+	for i := 0; i < 100; i++ {
+		d := p.backoff(2)
+		assert.True(t, d > 100*time.Millisecond && d <= 200*time.Millisecond)
+	}
+}
+
+// newFlakyServer answers with 503 Service Unavailable to the first fails requests.
+func newFlakyServer(t *testing.T, fails int32, calls *int32) *httptest.Server {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(calls, 1) <= fails {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		json.NewEncoder(w).Encode(H{
+			"jsonrpc": "2.0",
+			"id":      0,
+			"result":  H{"height": 10, "tx_hash": "abc"},
+		})
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestRetryReadOnly(t *testing.T) {
+	var calls int32
+	srv := newFlakyServer(t, 2, &calls)
+	cl := New(Config{
+		Address: srv.URL,
+		Retry:   &RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond},
+	})
+
+	resp, err := cl.GetHeight()
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(10), resp.Height)
+	assert.Equal(t, int32(3), calls)
+}
+
+func TestRetryNeverResendsSpending(t *testing.T) {
+	var calls int32
+	srv := newFlakyServer(t, 1, &calls)
+	cl := New(Config{
+		Address: srv.URL,
+		Retry:   &RetryPolicy{MaxAttempts: 5, InitialBackoff: time.Millisecond},
+	})
+
+	_, err := cl.Transfer(&RequestTransfer{})
+	var herr *HTTPError
+	assert.True(t, errors.As(err, &herr))
+	assert.Equal(t, http.StatusServiceUnavailable, herr.StatusCode)
+	assert.Equal(t, int32(1), calls)
+}
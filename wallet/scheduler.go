@@ -0,0 +1,169 @@
+package wallet
+
+import (
+	"container/heap"
+	"context"
+	"sync"
+)
+
+// Call priorities of DefaultCallPriority. Calls with a higher priority are
+// sent first when the scheduler has calls queued.
+const (
+	CallPriorityLow    = 0
+	CallPriorityNormal = 1
+	CallPriorityHigh   = 2
+)
+
+// SchedulerConfig configures the queue calls wait in before being sent to
+// monero-wallet-rpc, which handles one request at a time.
+type SchedulerConfig struct {
+	// MaxInFlight is the number of calls sent at the same time. Defaults to 1.
+	MaxInFlight int
+	// Priority ranks calls by method, higher priorities being sent first.
+	// Calls of the same priority are sent in order. Defaults to DefaultCallPriority.
+	Priority func(method string) int
+}
+
+// DefaultCallPriority puts spending calls and store first, then other mutating
+// calls and last read-only calls, so that polling never delays a payment.
+func DefaultCallPriority(method string) int {
+	if method == "store" {
+		return CallPriorityHigh
+	}
+	switch ClassifyMethod(method) {
+	case MethodSpending:
+		return CallPriorityHigh
+	case MethodMutating:
+		return CallPriorityNormal
+	}
+	return CallPriorityLow
+}
+
+type callPriorityKey struct{}
+
+// WithCallPriority returns a context making the scheduler queue calls
+// with the given priority instead of the one of their method.
+func WithCallPriority(ctx context.Context, priority int) context.Context {
+	return context.WithValue(ctx, callPriorityKey{}, priority)
+}
+
+// scheduler limits the number of calls in flight, queueing the others by priority.
+type scheduler struct {
+	max      int
+	priority func(string) int
+
+	mu       sync.Mutex
+	inFlight int
+	seq      uint64
+	queue    waitQueue
+}
+
+func newScheduler(cfg *SchedulerConfig) *scheduler {
+	s := &scheduler{
+		max:      cfg.MaxInFlight,
+		priority: cfg.Priority,
+	}
+	if s.max <= 0 {
+		s.max = 1
+	}
+	if s.priority == nil {
+		s.priority = DefaultCallPriority
+	}
+	return s
+}
+
+// waiter is a call waiting for its turn. ready is closed once it got it.
+type waiter struct {
+	priority int
+	seq      uint64
+	index    int
+	ready    chan struct{}
+}
+
+// acquire waits until a call of the given methods may be sent.
+func (s *scheduler) acquire(ctx context.Context, methods []string) error {
+	priority, ok := ctx.Value(callPriorityKey{}).(int)
+	if !ok {
+		priority = CallPriorityLow
+		for _, method := range methods {
+			if p := s.priority(method); p > priority {
+				priority = p
+			}
+		}
+	}
+
+	s.mu.Lock()
+	if s.inFlight < s.max && len(s.queue) == 0 {
+		s.inFlight++
+		s.mu.Unlock()
+		return nil
+	}
+	s.seq++
+	w := &waiter{priority: priority, seq: s.seq, ready: make(chan struct{})}
+	heap.Push(&s.queue, w)
+	s.mu.Unlock()
+
+	select {
+	case <-w.ready:
+		return nil
+	case <-ctx.Done():
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		select {
+		case <-w.ready:
+			// got the turn while giving up, hand it on
+			s.next()
+		default:
+			heap.Remove(&s.queue, w.index)
+		}
+		return ctx.Err()
+	}
+}
+
+// release ends a call, letting the next queued one go.
+func (s *scheduler) release() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.next()
+}
+
+// next hands the turn of a finished call to the first queued call.
+func (s *scheduler) next() {
+	if len(s.queue) == 0 {
+		s.inFlight--
+		return
+	}
+	close(heap.Pop(&s.queue).(*waiter).ready)
+}
+
+// waitQueue is a heap of waiters, by priority and then in order of arrival.
+type waitQueue []*waiter
+
+func (q waitQueue) Len() int { return len(q) }
+
+func (q waitQueue) Less(i, j int) bool {
+	if q[i].priority != q[j].priority {
+		return q[i].priority > q[j].priority
+	}
+	return q[i].seq < q[j].seq
+}
+
+func (q waitQueue) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+	q[i].index = i
+	q[j].index = j
+}
+
+func (q *waitQueue) Push(x interface{}) {
+	w := x.(*waiter)
+	w.index = len(*q)
+	*q = append(*q, w)
+}
+
+func (q *waitQueue) Pop() interface{} {
+	old := *q
+	w := old[len(old)-1]
+	old[len(old)-1] = nil
+	*q = old[:len(old)-1]
+	return w
+}
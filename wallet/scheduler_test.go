@@ -0,0 +1,118 @@
+package wallet
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func (s *scheduler) queued() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.queue)
+}
+
+func waitQueued(t *testing.T, s *scheduler, n int) {
+	deadline := time.Now().Add(5 * time.Second)
+	for s.queued() != n {
+		if time.Now().After(deadline) {
+			t.Fatalf("%d calls queued, expected %d", s.queued(), n)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestSchedulerPriority(t *testing.T) {
+	var (
+		mu    sync.Mutex
+		order []string
+	)
+	started, unblock := make(chan struct{}), make(chan struct{})
+	srv := newTestServer(t, func(req *rpcRequest) interface{} {
+		if req.Method == "get_balance" {
+			close(started)
+			<-unblock
+		}
+		mu.Lock()
+		order = append(order, req.Method)
+		mu.Unlock()
+		return H{}
+	})
+	cl := NewContextClient(Config{Address: srv.URL, Scheduler: &SchedulerConfig{}})
+	sched := cl.(*client).ep.(*endpoint).sched
+
+	var wg sync.WaitGroup
+	run := func(f func()) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			f()
+		}()
+	}
+	run(func() { cl.GetBalance(&RequestGetBalance{}) })
+	<-started
+	run(func() { cl.GetHeight() })
+	waitQueued(t, sched, 1)
+	run(func() { cl.CreateAddress(&RequestCreateAddress{}) })
+	waitQueued(t, sched, 2)
+	run(func() { cl.Transfer(&RequestTransfer{}) })
+	waitQueued(t, sched, 3)
+	run(func() { cl.Store() })
+	waitQueued(t, sched, 4)
+	run(func() { cl.GetHeightContext(WithCallPriority(context.Background(), CallPriorityHigh)) })
+	waitQueued(t, sched, 5)
+
+	close(unblock)
+	wg.Wait()
+	assert.Equal(t, []string{"get_balance", "transfer", "store", "get_height", "create_address", "get_height"}, order)
+}
+
+func TestSchedulerMaxInFlight(t *testing.T) {
+	var inFlight, max int32
+	srv := newTestServer(t, func(req *rpcRequest) interface{} {
+		n := atomic.AddInt32(&inFlight, 1)
+		for {
+			m := atomic.LoadInt32(&max)
+			if n <= m || atomic.CompareAndSwapInt32(&max, m, n) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+		atomic.AddInt32(&inFlight, -1)
+		return H{"height": 1}
+	})
+	cl := New(Config{Address: srv.URL, Scheduler: &SchedulerConfig{MaxInFlight: 2}})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := cl.GetHeight()
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int32(2), max)
+}
+
+func TestSchedulerCancelQueued(t *testing.T) {
+	s := newScheduler(&SchedulerConfig{})
+	assert.NoError(t, s.acquire(context.Background(), []string{"get_height"}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- s.acquire(ctx, []string{"get_height"}) }()
+	waitQueued(t, s, 1)
+	cancel()
+	assert.Equal(t, context.Canceled, <-done)
+	assert.Equal(t, 0, s.queued())
+
+	s.release()
+	assert.Equal(t, 0, s.inFlight)
+	assert.NoError(t, s.acquire(context.Background(), []string{"get_height"}))
+}
@@ -0,0 +1,177 @@
+package wallet
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrWalletSwitched is returned by WithWallet when another client opened a
+// different wallet on monero-wallet-rpc during the session.
+var ErrWalletSwitched = errors.New("wallet switched by another client")
+
+// SessionOption configures a WithWallet session.
+type SessionOption func(*sessionConfig)
+
+type sessionConfig struct {
+	store bool
+}
+
+// StoreBeforeClose saves the wallet file with Store before closing it.
+func StoreBeforeClose() SessionOption {
+	return func(c *sessionConfig) {
+		c.store = true
+	}
+}
+
+// WithWallet opens a wallet, hands fn a client bound to it and closes it again.
+//
+// The session holds the client exclusively: other sessions and calls made
+// through the client wait until it is closed, so fn must only use the Client
+// it is given. The primary address of the wallet is compared before and after
+// fn. If it changed, another client of monero-wallet-rpc switched wallets and
+// ErrWalletSwitched is returned; the wallet open then is not stored nor closed,
+// since it is not the one of the session. Otherwise the wallet is always
+// closed, even if fn failed or ctx is done.
+//
+// On a MultiClient, every call of the session goes to the primary endpoint.
+func (c *client) WithWallet(ctx context.Context, filename, password string, fn func(Client) error, opts ...SessionOption) error {
+	cfg := &sessionConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	if err := c.session.lock(ctx); err != nil {
+		return err
+	}
+	defer c.session.unlock()
+	sc := *c
+	sc.inSession = true
+	if p, ok := c.ep.(*pool); ok {
+		// the wallet is only open on the primary, which mutating calls go to
+		sc.ep = p.endpoints[0]
+	}
+
+	if err := sc.OpenWalletContext(ctx, &RequestOpenWallet{Filename: filename, Password: password}); err != nil {
+		return err
+	}
+	addr, err := sc.GetAddressContext(ctx, &RequestGetAddress{})
+	if err == nil {
+		err = fn(&sc)
+	}
+	// the wallet is closed even if ctx is done
+	ctx = context.WithoutCancel(ctx)
+	if addr != nil {
+		if cerr := sc.checkAddress(ctx, addr.Address); errors.Is(cerr, ErrWalletSwitched) {
+			return errors.Join(err, cerr)
+		} else if cerr != nil {
+			err = errors.Join(err, cerr)
+		}
+	}
+	if cfg.store {
+		if serr := sc.StoreContext(ctx); serr != nil {
+			err = errors.Join(err, serr)
+		}
+	}
+	if cerr := sc.CloseWalletContext(ctx); cerr != nil {
+		err = errors.Join(err, cerr)
+	}
+	return err
+}
+
+// checkAddress fails with ErrWalletSwitched if the primary address of the open wallet is not address.
+// The interceptors are skipped, so the address is not answered by a Cache.
+func (c *client) checkAddress(ctx context.Context, address string) error {
+	resp := &ResponseGetAddress{}
+	if err := c.invoke(ctx, "get_address", &RequestGetAddress{}, resp); err != nil {
+		return err
+	}
+	if resp.Address != address {
+		return fmt.Errorf("%w: expected %v, got %v", ErrWalletSwitched, address, resp.Address)
+	}
+	return nil
+}
+
+// sessionLock lets calls run concurrently, unless a session holds it exclusively.
+// Waiting sessions go before new calls, so sessions are not starved.
+type sessionLock struct {
+	mu      sync.Mutex
+	calls   int
+	held    bool
+	waiting int
+	changed chan struct{}
+}
+
+func newSessionLock() *sessionLock {
+	return &sessionLock{changed: make(chan struct{})}
+}
+
+// acquire takes the lock for a call.
+func (l *sessionLock) acquire(ctx context.Context) error {
+	return l.wait(ctx, false, func() bool {
+		if l.held || l.waiting > 0 {
+			return false
+		}
+		l.calls++
+		return true
+	})
+}
+
+// release gives back the lock of a call.
+func (l *sessionLock) release() {
+	l.mu.Lock()
+	l.calls--
+	l.broadcast()
+	l.mu.Unlock()
+}
+
+// lock takes the lock for a session, once no call runs anymore.
+func (l *sessionLock) lock(ctx context.Context) error {
+	return l.wait(ctx, true, func() bool {
+		if l.held || l.calls > 0 {
+			return false
+		}
+		l.held = true
+		return true
+	})
+}
+
+// unlock gives back the lock of a session.
+func (l *sessionLock) unlock() {
+	l.mu.Lock()
+	l.held = false
+	l.broadcast()
+	l.mu.Unlock()
+}
+
+// wait waits until take succeeds, counting sessions as waiting meanwhile.
+func (l *sessionLock) wait(ctx context.Context, session bool, take func() bool) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if session {
+		l.waiting++
+		defer func() {
+			l.waiting--
+			l.broadcast()
+		}()
+	}
+	for !take() {
+		changed := l.changed
+		l.mu.Unlock()
+		select {
+		case <-changed:
+			l.mu.Lock()
+		case <-ctx.Done():
+			l.mu.Lock()
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+// broadcast wakes up the waiters. l.mu must be held.
+func (l *sessionLock) broadcast() {
+	close(l.changed)
+	l.changed = make(chan struct{})
+}
@@ -0,0 +1,185 @@
+package wallet
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// walletRPC fakes the open wallet state of monero-wallet-rpc.
+type walletRPC struct {
+	mu    sync.Mutex
+	open  string
+	calls []string
+}
+
+func (w *walletRPC) serve(req *rpcRequest) interface{} {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.calls = append(w.calls, req.Method)
+	switch req.Method {
+	case "open_wallet":
+		params := &RequestOpenWallet{}
+		json.Unmarshal(req.Params, params)
+		w.open = params.Filename
+	case "close_wallet":
+		w.open = ""
+	case "get_address":
+		return H{"address": "address of " + w.open}
+	}
+	return H{}
+}
+
+func (w *walletRPC) switchTo(filename string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.open = filename
+}
+
+func (w *walletRPC) methods() []string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return append([]string(nil), w.calls...)
+}
+
+func TestWithWallet(t *testing.T) {
+	w := &walletRPC{}
+	srv := newTestServer(t, w.serve)
+	cl := NewContextClient(Config{Address: srv.URL})
+
+	err := cl.WithWallet(context.Background(), "alice", "pass", func(cl Client) error {
+		_, err := cl.GetBalance(&RequestGetBalance{})
+		return err
+	}, StoreBeforeClose())
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"open_wallet", "get_address", "get_balance", "get_address", "store", "close_wallet"}, w.methods())
+	assert.Equal(t, "", w.open)
+}
+
+func TestWithWalletClosesOnError(t *testing.T) {
+	w := &walletRPC{}
+	srv := newTestServer(t, w.serve)
+	cl := NewContextClient(Config{Address: srv.URL})
+
+	failed := errors.New("failed")
+	ctx, cancel := context.WithCancel(context.Background())
+	err := cl.WithWallet(ctx, "alice", "", func(Client) error {
+		cancel()
+		return failed
+	})
+	assert.True(t, errors.Is(err, failed))
+	assert.Equal(t, []string{"open_wallet", "get_address", "get_address", "close_wallet"}, w.methods())
+}
+
+func TestWithWalletSwitched(t *testing.T) {
+	w := &walletRPC{}
+	srv := newTestServer(t, w.serve)
+	cl := NewContextClient(Config{Address: srv.URL})
+
+	err := cl.WithWallet(context.Background(), "alice", "", func(Client) error {
+		w.switchTo("bob")
+		return nil
+	}, StoreBeforeClose())
+	assert.True(t, errors.Is(err, ErrWalletSwitched))
+	assert.Equal(t, []string{"open_wallet", "get_address", "get_address"}, w.methods())
+	assert.Equal(t, "bob", w.open)
+}
+
+func TestWithWalletSwitchedCached(t *testing.T) {
+	w := &walletRPC{}
+	srv := newTestServer(t, w.serve)
+	cache := NewCache(CacheConfig{})
+	cl := NewContextClient(Config{Address: srv.URL, Interceptors: []Interceptor{cache.Interceptor()}})
+
+	err := cl.WithWallet(context.Background(), "alice", "", func(Client) error {
+		w.switchTo("bob")
+		return nil
+	})
+	assert.True(t, errors.Is(err, ErrWalletSwitched))
+	assert.Equal(t, []string{"open_wallet", "get_address", "get_address"}, w.methods())
+}
+
+func TestWithWalletExclusive(t *testing.T) {
+	w := &walletRPC{}
+	srv := newTestServer(t, w.serve)
+	cl := NewContextClient(Config{Address: srv.URL})
+
+	inSession, unblock := make(chan struct{}), make(chan struct{})
+	done := make(chan error, 3)
+	go func() {
+		done <- cl.WithWallet(context.Background(), "alice", "", func(Client) error {
+			close(inSession)
+			<-unblock
+			return nil
+		})
+	}()
+	<-inSession
+
+	go func() {
+		done <- cl.WithWallet(context.Background(), "bob", "", func(Client) error { return nil })
+	}()
+	go func() {
+		_, err := cl.GetHeight()
+		done <- err
+	}()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.Equal(t, context.DeadlineExceeded, cl.WithWallet(ctx, "carol", "", func(Client) error { return nil }))
+
+	time.Sleep(10 * time.Millisecond)
+	assert.Equal(t, []string{"open_wallet", "get_address"}, w.methods())
+	close(unblock)
+	for i := 0; i < 3; i++ {
+		assert.NoError(t, <-done)
+	}
+	// the waiting session goes before the call
+	assert.Equal(t, []string{
+		"open_wallet", "get_address", "get_address", "close_wallet",
+		"open_wallet", "get_address", "get_address", "close_wallet",
+		"get_height",
+	}, w.methods())
+}
+
+func TestWithWalletMulti(t *testing.T) {
+	primary, secondary := &walletRPC{}, &walletRPC{open: "bob"}
+	// the primary answers health checks slower, so read-only calls prefer the secondary
+	psrv := newTestServer(t, func(req *rpcRequest) interface{} {
+		if req.Method == "get_version" {
+			time.Sleep(20 * time.Millisecond)
+		}
+		return primary.serve(req)
+	})
+	ssrv := newTestServer(t, secondary.serve)
+	cl, err := NewMulti(MultiConfig{Endpoints: []Config{{Address: psrv.URL}, {Address: ssrv.URL}}})
+	assert.NoError(t, err)
+	cl.CheckHealth(context.Background())
+	resp, err := cl.GetAddress(&RequestGetAddress{})
+	assert.NoError(t, err)
+	assert.Equal(t, "address of bob", resp.Address)
+
+	err = cl.WithWallet(context.Background(), "alice", "pass", func(cl Client) error {
+		resp, err := cl.GetAddress(&RequestGetAddress{})
+		if err == nil {
+			assert.Equal(t, "address of alice", resp.Address)
+		}
+		return err
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"open_wallet", "get_address", "get_address", "get_address", "close_wallet"}, withoutHealthChecks(primary.methods()))
+}
+
+// withoutHealthChecks drops the health checks of a MultiClient from methods.
+func withoutHealthChecks(methods []string) []string {
+	var out []string
+	for _, m := range methods {
+		if m != "get_version" && m != "get_height" {
+			out = append(out, m)
+		}
+	}
+	return out
+}
@@ -0,0 +1,211 @@
+package wallet
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// ErrResponseTooLarge is returned when a response exceeds Config.MaxResponseSize.
+var ErrResponseTooLarge = errors.New("response too large")
+
+// streamDecoder is implemented by call results decoding the response body
+// themselves, instead of it being unmarshaled at once.
+type streamDecoder interface {
+	decodeResponse(body io.Reader) error
+}
+
+type streamKey struct{}
+
+// streaming tells whether the call of ctx is streamed, and
+// so not subject to Config.MaxResponseSize.
+func streaming(ctx context.Context) bool {
+	return ctx.Value(streamKey{}) != nil
+}
+
+// partialStreamError is returned when a stream stops after transfers were
+// handed to the callback, be it broken or stopped by the callback. Sending the
+// call again would hand them over twice, so it is not retried, neither by the
+// endpoint nor on another endpoint.
+type partialStreamError struct {
+	err error
+}
+
+func (e *partialStreamError) Error() string {
+	return "stream stopped after delivering transfers: " + e.err.Error()
+}
+
+func (e *partialStreamError) Unwrap() error {
+	return e.err
+}
+
+func (e *partialStreamError) Retryable() bool {
+	return false
+}
+
+// transferStream decodes a get_transfers response transfer by transfer.
+type transferStream struct {
+	fn func(*Transfer) error
+	// delivered is set once fn was called.
+	delivered bool
+}
+
+// decodeResponse walks the JSON-RPC response with a token decoder, handing
+// the transfers of the in, out, pending, failed and pool arrays to fn as
+// they are decoded.
+func (s *transferStream) decodeResponse(body io.Reader) error {
+	err := s.decode(body)
+	if err != nil && s.delivered {
+		return &partialStreamError{err: err}
+	}
+	return err
+}
+
+func (s *transferStream) decode(body io.Reader) error {
+	dec := json.NewDecoder(body)
+	if err := expectDelim(dec, '{'); err != nil {
+		return err
+	}
+	result := false
+	for dec.More() {
+		key, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		switch key {
+		case "result":
+			tok, err := dec.Token()
+			if err != nil {
+				return err
+			}
+			if tok == nil {
+				// null, the error follows
+				continue
+			}
+			if tok != json.Delim('{') {
+				return fmt.Errorf("invalid response: expected {, got %v", tok)
+			}
+			if err := s.decodeResult(dec); err != nil {
+				return err
+			}
+			result = true
+		case "error":
+			var raw json.RawMessage
+			if err := dec.Decode(&raw); err != nil {
+				return err
+			}
+			if isNull(raw) {
+				continue
+			}
+			rpcErr := &struct {
+				Code    ErrorCode `json:"code"`
+				Message string    `json:"message"`
+			}{}
+			if err := json.Unmarshal(raw, rpcErr); err != nil {
+				return err
+			}
+			return &WalletError{Code: rpcErr.Code, Message: rpcErr.Message}
+		default:
+			if err := skipValue(dec); err != nil {
+				return err
+			}
+		}
+	}
+	if err := expectDelim(dec, '}'); err != nil {
+		return err
+	}
+	if !result {
+		return ErrNullResult
+	}
+	return nil
+}
+
+// decodeResult decodes the result object, whose opening delimiter was read.
+func (s *transferStream) decodeResult(dec *json.Decoder) error {
+	for dec.More() {
+		key, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		switch key {
+		case "in", "out", "pending", "failed", "pool":
+			if err := expectDelim(dec, '['); err != nil {
+				return err
+			}
+			for dec.More() {
+				transfer := &Transfer{}
+				if err := dec.Decode(transfer); err != nil {
+					return err
+				}
+				s.delivered = true
+				if err := s.fn(transfer); err != nil {
+					return err
+				}
+			}
+			if err := expectDelim(dec, ']'); err != nil {
+				return err
+			}
+		default:
+			if err := skipValue(dec); err != nil {
+				return err
+			}
+		}
+	}
+	return expectDelim(dec, '}')
+}
+
+func expectDelim(dec *json.Decoder, delim json.Delim) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if tok != delim {
+		return fmt.Errorf("invalid response: expected %v, got %v", delim, tok)
+	}
+	return nil
+}
+
+// skipValue consumes the next value without keeping it in memory.
+func skipValue(dec *json.Decoder) error {
+	depth := 0
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		switch tok {
+		case json.Delim('{'), json.Delim('['):
+			depth++
+		case json.Delim('}'), json.Delim(']'):
+			depth--
+		}
+		if depth == 0 {
+			return nil
+		}
+	}
+}
+
+// limitBody fails reads going past max bytes with ErrResponseTooLarge.
+type limitBody struct {
+	r    io.Reader
+	left int64
+	max  int64
+}
+
+func newLimitBody(r io.Reader, max int64) *limitBody {
+	return &limitBody{r: r, left: max + 1, max: max}
+}
+
+func (l *limitBody) Read(p []byte) (int, error) {
+	if int64(len(p)) > l.left {
+		p = p[:l.left]
+	}
+	n, err := l.r.Read(p)
+	l.left -= int64(n)
+	if l.left <= 0 {
+		return n, fmt.Errorf("%w: more than %d bytes", ErrResponseTooLarge, l.max)
+	}
+	return n, err
+}
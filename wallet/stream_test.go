@@ -0,0 +1,129 @@
+package wallet
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStreamTransfers(t *testing.T) {
+	srv := newTestServer(t, func(req *rpcRequest) interface{} {
+		return H{
+			"in":      []H{{"txid": "a", "amount": 1, "type": "in"}, {"txid": "b", "amount": 2, "type": "in"}},
+			"unknown": H{"nested": []int{1, 2}},
+			"out":     []H{{"txid": "c", "amount": 3, "type": "out", "destinations": []H{{"address": "x", "amount": 3}}}},
+			"pool":    []H{},
+		}
+	})
+	cl := New(Config{Address: srv.URL, MaxResponseSize: 16})
+
+	var txids []string
+	err := cl.StreamTransfers(&RequestGetTransfers{In: true, Out: true}, func(tr *Transfer) error {
+		txids = append(txids, tr.TxID+"/"+tr.Type)
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a/in", "b/in", "c/out"}, txids)
+
+	stop := errors.New("stop")
+	n := 0
+	err = cl.StreamTransfers(&RequestGetTransfers{In: true}, func(tr *Transfer) error {
+		n++
+		return stop
+	})
+	assert.True(t, errors.Is(err, stop))
+	assert.Equal(t, 1, n)
+
+	_, err = cl.GetTransfers(&RequestGetTransfers{In: true})
+	assert.True(t, errors.Is(err, ErrResponseTooLarge))
+}
+
+func TestStreamTransfersError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"id":0,"jsonrpc":"2.0","error":{"code":-13,"message":"No wallet file"}}`)
+	}))
+	defer srv.Close()
+	cl := New(Config{Address: srv.URL})
+
+	err := cl.StreamTransfers(&RequestGetTransfers{In: true}, func(*Transfer) error { return nil })
+	assert.True(t, errors.Is(err, ErrNotOpen))
+}
+
+func TestStreamTransfersNoRetry(t *testing.T) {
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			// the connection breaks in the middle of the response
+			fmt.Fprint(w, `{"id":1,"jsonrpc":"2.0","result":{"in":[{"txid":"a"},{"txid":"b"},`)
+			return
+		}
+		fmt.Fprint(w, `{"id":1,"jsonrpc":"2.0","error":null,"result":{"in":[{"txid":"a"},{"txid":"b"}]}}`)
+	}))
+	defer srv.Close()
+	cl := New(Config{Address: srv.URL, Retry: &RetryPolicy{MaxAttempts: 3}})
+
+	var txids []string
+	collect := func(tr *Transfer) error {
+		txids = append(txids, tr.TxID)
+		return nil
+	}
+	err := cl.StreamTransfers(&RequestGetTransfers{In: true}, collect)
+	assert.Error(t, err)
+	assert.False(t, IsRetryable(err))
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, []string{"a", "b"}, txids)
+
+	txids = nil
+	assert.NoError(t, cl.StreamTransfers(&RequestGetTransfers{In: true}, collect))
+	assert.Equal(t, []string{"a", "b"}, txids)
+}
+
+func TestStreamTransfersCallbackNoRetry(t *testing.T) {
+	calls := 0
+	srv := newTestServer(t, func(req *rpcRequest) interface{} {
+		calls++
+		return H{"in": []H{{"txid": "a"}, {"txid": "b"}}}
+	})
+	cl := New(Config{Address: srv.URL, Retry: &RetryPolicy{MaxAttempts: 3}})
+
+	// a retryable error of the callback must not send the call again
+	var txids []string
+	err := cl.StreamTransfers(&RequestGetTransfers{In: true}, func(tr *Transfer) error {
+		txids = append(txids, tr.TxID)
+		if tr.TxID == "b" {
+			return context.DeadlineExceeded
+		}
+		return nil
+	})
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+	assert.False(t, IsRetryable(err))
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, []string{"a", "b"}, txids)
+}
+
+func TestMaxResponseSize(t *testing.T) {
+	srv := newTestServer(t, func(req *rpcRequest) interface{} {
+		return H{"height": 1}
+	})
+	// the response is {"jsonrpc":"2.0","id":...,"result":{"height":1}} plus a newline
+	_, err := New(Config{Address: srv.URL, MaxResponseSize: 1 << 10}).GetHeight()
+	assert.NoError(t, err)
+	_, err = New(Config{Address: srv.URL, MaxResponseSize: 20}).GetHeight()
+	assert.True(t, errors.Is(err, ErrResponseTooLarge))
+	assert.False(t, IsRetryable(err))
+
+	// without a Content-Length the body is cut while reading
+	b, err := io.ReadAll(newLimitBody(strings.NewReader("abcd"), 4))
+	assert.NoError(t, err)
+	assert.Equal(t, "abcd", string(b))
+	_, err = io.ReadAll(newLimitBody(strings.NewReader("abcd"), 3))
+	assert.True(t, errors.Is(err, ErrResponseTooLarge))
+}
@@ -13,6 +13,8 @@ type SignedKeyImage struct {
 }
 
 // *** RPC STRUCTS ***
+// Fields carrying secrets (passwords, private keys, seeds, multisig info) are
+// tagged with `redact:"true"` so they are masked by Redact.
 // GetBalance()
 type RequestGetBalance struct {
 	// Return balance for this account.
@@ -233,7 +235,7 @@ type RequestTransfer struct {
 	// (Optional) Random 32-byte/64-character hex string to identify a transaction.
 	PaymentID string `json:"payment_id"`
 	// (Optional) Return the transaction key after sending.
-	GetTxKey bool `json:"get_tx_key"`
+	GetTxKey bool `json:"get_tx_key" redact:"false"`
 	// (Optional) If true, the newly created transaction will not be relayed to the monero network. (Defaults to false)
 	DoNotRelay bool `json:"do_not_relay,omitempty"`
 	// (Optional) Return the transaction as hex string after sending (Defaults to false)
@@ -253,7 +255,7 @@ type ResponseTransfer struct {
 	// String for the publically searchable transaction hash.
 	TxHash string `json:"tx_hash"`
 	// String for the transaction key if get_tx_key is true, otherwise, blank string.
-	TxKey      string `json:"tx_key"`
+	TxKey      string `json:"tx_key" redact:"true"`
 	TxMetadata string `json:"tx_metadata"` // TxMetadata tx_metadata - Set of transaction metadata needed to relay this transfer later, if get_tx_metadata is true.
 
 	// String. Set of unsigned tx for cold-signing purposes.
@@ -293,7 +295,7 @@ type ResponseTransferSplit struct {
 	// The tx hashes of every transaction.
 	TxHashList []string `json:"tx_hash_list"`
 	// The transaction keys for every transaction.
-	TxKeyList []string `json:"tx_key_list"`
+	TxKeyList []string `json:"tx_key_list" redact:"true"`
 	// The amount transferred for every transaction.
 	AmountList []uint64 `json:"amount_list"`
 	// The amount of fees paid for every transaction.
@@ -337,7 +339,7 @@ type ResponseSubmitTransfer struct {
 // SweepDust()
 type RequestSweepDust struct {
 	// (Optional) Return the transaction keys after sending.
-	GetTxKeys bool `json:"get_tx_keys"`
+	GetTxKeys bool `json:"get_tx_keys" redact:"false"`
 	// (Optional) If true, the newly created transaction will not be relayed to the monero network. (Defaults to false)
 	DoNotRelay bool `json:"do_not_relay,omitempty"`
 	// (Optional) Return the transactions as hex string after sending. (Defaults to false)
@@ -349,7 +351,7 @@ type ResponseSweepDust struct {
 	// The tx hashes of every transaction.
 	TxHashList []string `json:"tx_hash_list"`
 	// The transaction keys for every transaction.
-	TxKeyList []string `json:"tx_key_list"`
+	TxKeyList []string `json:"tx_key_list" redact:"true"`
 	//  The amount transferred for every transaction.
 	AmountList []uint64 `json:"amount_list"`
 	//  The amount of fees paid for every transaction.
@@ -385,7 +387,7 @@ type RequestSweepAll struct {
 	//  (Optional) Random 32-byte/64-character hex string to identify a transaction.
 	PaymentID string `json:"payment_id"`
 	//  (Optional) Return the transaction keys after sending.
-	GetTxKeys bool `json:"get_tx_keys"`
+	GetTxKeys bool `json:"get_tx_keys" redact:"false"`
 	//  (Optional) Include outputs below this amount.
 	BelowAmount uint64 `json:"below_amount"`
 	//  (Optional) If true, do not relay this sweep transfer. (Defaults to false)
@@ -399,7 +401,7 @@ type ResponseSweepAll struct {
 	// The tx hashes of every transaction.
 	TxHashList []string `json:"tx_hash_list"`
 	// The transaction keys for every transaction.
-	TxKeyList []string `json:"tx_key_list"`
+	TxKeyList []string `json:"tx_key_list" redact:"true"`
 	// The amount transferred for every transaction.
 	AmountList []uint64 `json:"amount_list"`
 	// The amount of fees paid for every transaction.
@@ -449,7 +451,7 @@ type ResponseSweepSingle struct {
 	// The tx hashes of every transaction.
 	TxHashList []string `json:"tx_hash_list"`
 	// The transaction keys for every transaction.
-	TxKeyList []string `json:"tx_key_list"`
+	TxKeyList []string `json:"tx_key_list" redact:"true"`
 	// The amount transferred for every transaction.
 	AmountList []uint64 `json:"amount_list"`
 	// The amount of fees paid for every transaction.
@@ -549,9 +551,11 @@ type RequestIncomingTransfers struct {
 }
 type ResponseIncomingTransfers struct {
 	// list of transfers:
-	Transfers struct {
+	Transfers []struct {
 		// Amount of this transfer.
 		Amount uint64 `json:"amount"`
+		// Indicates if this output is frozen, see Freeze.
+		Frozen bool `json:"frozen"`
 		// Mostly internal use, can be ignored by most users.
 		GlobalIndex uint64 `json:"global_index"`
 		// Key image for the incoming transfer's unspent output (empty unless verbose is true).
@@ -574,7 +578,7 @@ type RequestQueryKey struct {
 }
 type ResponseQueryKey struct {
 	// The view key will be hex encoded, while the mnemonic will be a string of words.
-	Key string `json:"key"`
+	Key string `json:"key" redact:"true"`
 }
 
 // MakeIntegratedAddress()
@@ -605,6 +609,12 @@ type ResponseSplitIntegratedAddress struct {
 	StandardAddress string `json:"standard_address"`
 }
 
+// RescanBlockchain()
+type RequestRescanBlockchain struct {
+	// (Optional) If true, do a hard rescan, clearing everything the wallet knows about the blockchain first. (Defaults to false)
+	Hard bool `json:"hard,omitempty"`
+}
+
 // SetTxNotes()
 type RequestSetTxNotes struct {
 	// Transaction ids
@@ -648,7 +658,7 @@ type RequestGetTxKey struct {
 }
 type ResponseGetTxKey struct {
 	// Transaction secret key.
-	TxKey string `json:"tx_key"`
+	TxKey string `json:"tx_key" redact:"true"`
 }
 
 // CheckTxKey()
@@ -656,7 +666,7 @@ type RequestCheckTxKey struct {
 	// Transaction id.
 	TxID string `json:"txid"`
 	// Transaction secret key.
-	TxKey string `json:"tx_key"`
+	TxKey string `json:"tx_key" redact:"true"`
 	// Destination public address of the transaction.
 	Address string `json:"address"`
 }
@@ -991,6 +1001,14 @@ type ResponseRefresh struct {
 	ReceivedMoney bool `json:"received_money"`
 }
 
+// AutoRefresh()
+type RequestAutoRefresh struct {
+	// Enable or disable automatic refreshing.
+	Enable bool `json:"enable"`
+	// (Optional) The period of the wallet refresh cycle (i.e. time between refreshes) in seconds.
+	Period uint64 `json:"period,omitempty"`
+}
+
 // StartMining()
 type RequestStartMining struct {
 	// Number of threads created for mining.
@@ -1012,7 +1030,7 @@ type RequestCreateWallet struct {
 	// Wallet file name.
 	Filename string `json:"filename"`
 	// (Optional) password to protect the wallet.
-	Password string `json:"password"`
+	Password string `json:"password" redact:"true"`
 	// Language for your wallets' seed.
 	Language string `json:"language"`
 }
@@ -1026,11 +1044,11 @@ type RequestGenerateFromKeys struct {
 	// The wallet's primary address.
 	Address string `json:"address"`
 	// (Optional - omit to create a view-only wallet) The wallet's private spend key.
-	SpendKey string `json:"spendkey"`
+	SpendKey string `json:"spendkey" redact:"true"`
 	// The wallet's private view key.
-	ViewKey string `json:"viewkey"`
+	ViewKey string `json:"viewkey" redact:"true"`
 	// The wallet's password.
-	Password string `json:"password"`
+	Password string `json:"password" redact:"true"`
 	// (Optional) If true, save the current wallet before generating the new wallet. (Defaults to true)
 	AutoSaveCurrent bool `json:"autosave_current"`
 	// (Optional) Language for your wallets' seed. (Defaults to "English")
@@ -1045,20 +1063,50 @@ type ResponseGenerateFromKeys struct {
 	Info string `json:"info"`
 }
 
+// RestoreDeterministicWallet()
+type RequestRestoreDeterministicWallet struct {
+	// (Optional) The block height to restore the wallet from. (Defaults to 0)
+	RestoreHeight int64 `json:"restore_height"`
+	// The wallet's file name on the RPC server.
+	Filename string `json:"filename"`
+	// Mnemonic phrase of the wallet to restore.
+	Seed string `json:"seed" redact:"true"`
+	// (Optional) Passphrase the seed was offset with.
+	SeedOffset string `json:"seed_offset" redact:"true"`
+	// The wallet's password.
+	Password string `json:"password" redact:"true"`
+	// (Optional) Language of the mnemonic phrase, in case the old language is invalid. (Defaults to "English")
+	Language string `json:"language"`
+	// (Optional) If true, save the current wallet before restoring the new wallet. (Defaults to true if nil)
+	AutoSaveCurrent *bool `json:"autosave_current,omitempty"`
+}
+
+// RestoreDeterministicWallet()
+type ResponseRestoreDeterministicWallet struct {
+	// The wallet's address.
+	Address string `json:"address"`
+	// Message stating whether the wallet was restored successfully.
+	Info string `json:"info"`
+	// Mnemonic phrase of the restored wallet, updated if the wallet was restored from a deprecated-style mnemonic phrase.
+	Seed string `json:"seed" redact:"true"`
+	// Indicates if the restored wallet was created from a deprecated-style mnemonic phrase.
+	WasDeprecated bool `json:"was_deprecated"`
+}
+
 // OpenWallet()
 type RequestOpenWallet struct {
 	// Wallet name stored in –wallet-dir.
 	Filename string `json:"filename"`
 	// (Optional) only needed if the wallet has a password defined.
-	Password string `json:"password"`
+	Password string `json:"password" redact:"true"`
 }
 
 // ChangeWalletPassword()
 type RequestChangeWalletPassword struct {
 	// (Optional) Current wallet password, if defined.
-	OldPassword string `json:"old_password"`
+	OldPassword string `json:"old_password" redact:"true"`
 	// (Optional) New wallet password, if not blank.
-	NewPassword string `json:"new_password"`
+	NewPassword string `json:"new_password" redact:"true"`
 }
 
 // IsMultisig()
@@ -1075,35 +1123,35 @@ type ResponseIsMultisig struct {
 // PrepareMultisig()
 type ResponsePrepareMultisig struct {
 	// Multisig string to share with peers to create the multisig wallet.
-	MultisigInfo string `json:"multisig_info"`
+	MultisigInfo string `json:"multisig_info" redact:"true"`
 }
 
 // MakeMultisig()
 type RequestMakeMultisig struct {
 	// List of multisig string from peers.
-	MultisigInfo []string `json:"multisig_info"`
+	MultisigInfo []string `json:"multisig_info" redact:"true"`
 	// Amount of signatures needed to sign a transfer. Must be less or equal than the amount of signature in multisig_info.
 	Threshold uint64 `json:"threshold"`
 	// Wallet password
-	Password string `json:"password"`
+	Password string `json:"password" redact:"true"`
 }
 type ResponseMakeMultisig struct {
 	// Multisig wallet address.
 	Address string `json:"address"`
 	// Multisig string to share with peers to create the multisig wallet (extra step for N-1/N wallets).
-	MultisigInfo string `json:"multisig_info"`
+	MultisigInfo string `json:"multisig_info" redact:"true"`
 }
 
 // ExportMultisigInfo()
 type ResponseExportMultisigInfo struct {
 	// Multisig info in hex format for other participants.
-	Info string `json:"info"`
+	Info string `json:"info" redact:"true"`
 }
 
 // ImportMultisigInfo()
 type RequestImportMultisigInfo struct {
 	// List of multisig info in hex format from other participants.
-	Info []string `json:"info"`
+	Info []string `json:"info" redact:"true"`
 }
 type ResponseImportMultisigInfo struct {
 	// Number of outputs signed with those multisig info.
@@ -1113,9 +1161,9 @@ type ResponseImportMultisigInfo struct {
 // FinalizeMultisig()
 type RequestFinalizeMultisig struct {
 	// List of multisig string from peers.
-	MultisigInfo []string `json:"multisig_info"`
+	MultisigInfo []string `json:"multisig_info" redact:"true"`
 	// Wallet password
-	Password string `json:"password"`
+	Password string `json:"password" redact:"true"`
 }
 type ResponseFinalizeMultisig struct {
 	// Multisig wallet address.
@@ -1149,3 +1197,73 @@ type ResponseGetVersion struct {
 	// RPC version, formatted with Major * 2^16 + Minor (Major encoded over the first 16 bits, and Minor over the last 16 bits).
 	Version uint64 `json:"version"`
 }
+
+// Freeze()
+type RequestFreeze struct {
+	// Key image of the output to freeze.
+	KeyImage string `json:"key_image"`
+}
+
+// Thaw()
+type RequestThaw struct {
+	// Key image of the output to thaw.
+	KeyImage string `json:"key_image"`
+}
+
+// Frozen()
+type RequestFrozen struct {
+	// Key image of the output to check.
+	KeyImage string `json:"key_image"`
+}
+type ResponseFrozen struct {
+	// States if the output is frozen.
+	Frozen bool `json:"frozen"`
+}
+
+// SetDaemon()
+type RequestSetDaemon struct {
+	// (Optional) The URL of the daemon to connect to. Disconnects the wallet if empty.
+	Address string `json:"address"`
+	// (Optional) If false, some RPC wallet methods will be disabled. (Defaults to false)
+	Trusted bool `json:"trusted"`
+	// (Optional) SSLDisabled, SSLEnabled or SSLAutodetect. (Defaults to SSLAutodetect)
+	SSLSupport SSLSupport `json:"ssl_support,omitempty"`
+	// (Optional) Path to the private key of the client certificate, on the RPC server.
+	SSLPrivateKeyPath string `json:"ssl_private_key_path,omitempty"`
+	// (Optional) Path to the client certificate, on the RPC server.
+	SSLCertificatePath string `json:"ssl_certificate_path,omitempty"`
+	// (Optional) Path to the CA file the daemon certificate is verified with, on the RPC server.
+	SSLCAFile string `json:"ssl_ca_file,omitempty"`
+	// (Optional) Fingerprints of the daemon certificates which are accepted.
+	SSLAllowedFingerprints []string `json:"ssl_allowed_fingerprints,omitempty"`
+	// (Optional) Accept any daemon certificate. (Defaults to false)
+	SSLAllowAnyCert bool `json:"ssl_allow_any_cert"`
+	// (Optional) Username for the RPC login of the daemon.
+	Username string `json:"username,omitempty"`
+	// (Optional) Password for the RPC login of the daemon.
+	Password string `json:"password,omitempty" redact:"true"`
+}
+
+// EstimateTxSizeAndWeight()
+type RequestEstimateTxSizeAndWeight struct {
+	// Number of inputs of the transaction.
+	NInputs uint64 `json:"n_inputs"`
+	// Number of outputs of the transaction, including change.
+	NOutputs uint64 `json:"n_outputs"`
+	// (Optional) Ring size of the inputs. (Defaults to the ring size of the current fork)
+	RingSize uint64 `json:"ring_size,omitempty"`
+	// Whether the transaction is a RingCT one, which all transactions are nowadays.
+	RCT bool `json:"rct"`
+}
+type ResponseEstimateTxSizeAndWeight struct {
+	// Estimated size of the transaction in bytes.
+	Size uint64 `json:"size"`
+	// Estimated weight of the transaction, which the fee is based on.
+	Weight uint64 `json:"weight"`
+}
+
+// GetDefaultFeePriority()
+type ResponseGetDefaultFeePriority struct {
+	// Priority used by transfers made with PriorityDefault.
+	Priority Priority `json:"priority"`
+}
@@ -0,0 +1,107 @@
+package wallet
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// TLSConfig holds the TLS settings for monero-wallet-rpc serving HTTPS
+// (--rpc-ssl, --rpc-ssl-certificate, --rpc-ssl-private-key).
+type TLSConfig struct {
+	// CertFile and KeyFile are a PEM encoded client certificate and its
+	// private key, for servers requiring mutual authentication.
+	CertFile string
+	KeyFile  string
+	// CAFile is a PEM bundle of the certificate authorities to verify the
+	// server certificate with, instead of the system ones.
+	CAFile string
+	// AllowedFingerprints are the hex encoded SHA-256 fingerprints of the
+	// accepted server certificates, colons being optional, as with
+	// --rpc-ssl-allowed-fingerprints. If set without CAFile, self-signed
+	// certificates are accepted as long as their fingerprint matches.
+	AllowedFingerprints []string
+	// ServerName overrides the host name the server certificate is verified for.
+	ServerName string
+}
+
+// FingerprintError is returned when the server certificate matches none of
+// the TLSConfig.AllowedFingerprints.
+type FingerprintError struct {
+	// Fingerprint is the hex encoded SHA-256 fingerprint of the server certificate.
+	Fingerprint string
+}
+
+func (e *FingerprintError) Error() string {
+	return fmt.Sprintf("server certificate fingerprint %v is not allowed", e.Fingerprint)
+}
+
+// Retryable reports false, a mismatching certificate does not go away by retrying.
+func (e *FingerprintError) Retryable() bool {
+	return false
+}
+
+// config returns a copy of base with the settings of c applied.
+func (c *TLSConfig) config(base *tls.Config) (*tls.Config, error) {
+	conf := &tls.Config{}
+	if base != nil {
+		conf = base.Clone()
+	}
+	if c.ServerName != "" {
+		conf.ServerName = c.ServerName
+	}
+	if c.CertFile != "" || c.KeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(c.CertFile, c.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("loading client certificate: %w", err)
+		}
+		conf.Certificates = []tls.Certificate{cert}
+	}
+	if c.CAFile != "" {
+		pem, err := os.ReadFile(c.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("loading CA bundle: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in CA bundle %v", c.CAFile)
+		}
+		conf.RootCAs = pool
+	}
+	if len(c.AllowedFingerprints) == 0 {
+		return conf, nil
+	}
+
+	pins := make([][]byte, len(c.AllowedFingerprints))
+	for i, fp := range c.AllowedFingerprints {
+		pin, err := hex.DecodeString(strings.ReplaceAll(fp, ":", ""))
+		if err != nil || len(pin) != sha256.Size {
+			return nil, fmt.Errorf("invalid SHA-256 fingerprint %q", fp)
+		}
+		pins[i] = pin
+	}
+	// Without CAs the fingerprint is the only check, the chain
+	// and host name of self-signed certificates can not be verified.
+	if c.CAFile == "" {
+		conf.InsecureSkipVerify = true
+	}
+	conf.VerifyConnection = func(cs tls.ConnectionState) error {
+		if len(cs.PeerCertificates) == 0 {
+			return errors.New("no server certificate")
+		}
+		sum := sha256.Sum256(cs.PeerCertificates[0].Raw)
+		for _, pin := range pins {
+			if bytes.Equal(pin, sum[:]) {
+				return nil
+			}
+		}
+		return &FingerprintError{Fingerprint: hex.EncodeToString(sum[:])}
+	}
+	return conf, nil
+}
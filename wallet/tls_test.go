@@ -0,0 +1,147 @@
+package wallet
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math/big"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTLSTestServer(t *testing.T, clientCAs *x509.CertPool) *httptest.Server {
+	srv := httptest.NewUnstartedServer(rpcHandler(func(req *rpcRequest) interface{} {
+		return H{"height": 42}
+	}))
+	if clientCAs != nil {
+		srv.TLS = &tls.Config{
+			ClientAuth: tls.RequireAndVerifyClientCert,
+			ClientCAs:  clientCAs,
+		}
+	}
+	srv.Config.ErrorLog = log.New(io.Discard, "", 0)
+	srv.StartTLS()
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func writePEM(t *testing.T, name, typ string, der []byte) string {
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: typ, Bytes: der}), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func fingerprint(cert *x509.Certificate) string {
+	sum := sha256.Sum256(cert.Raw)
+	parts := make([]string, len(sum))
+	for i, b := range sum {
+		parts[i] = fmt.Sprintf("%02X", b)
+	}
+	return strings.Join(parts, ":")
+}
+
+func TestTLSCAFile(t *testing.T) {
+	srv := newTLSTestServer(t, nil)
+
+	_, err := New(Config{Address: srv.URL}).GetHeight()
+	assert.Error(t, err)
+
+	cl := New(Config{
+		Address: srv.URL,
+		TLS:     &TLSConfig{CAFile: writePEM(t, "ca.pem", "CERTIFICATE", srv.Certificate().Raw)},
+	})
+	resp, err := cl.GetHeight()
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(42), resp.Height)
+}
+
+func TestTLSFingerprint(t *testing.T) {
+	srv := newTLSTestServer(t, nil)
+
+	cl := New(Config{
+		Address: srv.URL,
+		TLS:     &TLSConfig{AllowedFingerprints: []string{fingerprint(srv.Certificate())}},
+	})
+	_, err := cl.GetHeight()
+	assert.NoError(t, err)
+
+	cl = New(Config{
+		Address: srv.URL,
+		TLS:     &TLSConfig{AllowedFingerprints: []string{strings.Repeat("ab", sha256.Size)}},
+	})
+	_, err = cl.GetHeight()
+	var ferr *FingerprintError
+	assert.True(t, errors.As(err, &ferr))
+	assert.Equal(t, strings.ToLower(strings.ReplaceAll(fingerprint(srv.Certificate()), ":", "")), ferr.Fingerprint)
+	assert.False(t, IsRetryable(err))
+}
+
+func TestTLSClientCertificate(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "wallet client"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, _ := x509.ParseCertificate(der)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(cert)
+	srv := newTLSTestServer(t, clientCAs)
+	pin := []string{fingerprint(srv.Certificate())}
+
+	_, err = New(Config{Address: srv.URL, TLS: &TLSConfig{AllowedFingerprints: pin}}).GetHeight()
+	assert.Error(t, err)
+
+	cl := New(Config{
+		Address: srv.URL,
+		TLS: &TLSConfig{
+			CertFile:            writePEM(t, "client.pem", "CERTIFICATE", der),
+			KeyFile:             writePEM(t, "client.key", "EC PRIVATE KEY", keyDER),
+			AllowedFingerprints: pin,
+		},
+	})
+	_, err = cl.GetHeight()
+	assert.NoError(t, err)
+}
+
+func TestTLSConfigErrors(t *testing.T) {
+	for _, conf := range []*TLSConfig{
+		{CAFile: "/nonexistent/ca.pem"},
+		{CertFile: "/nonexistent/client.pem"},
+		{AllowedFingerprints: []string{"not hex"}},
+		{AllowedFingerprints: []string{"abcd"}},
+	} {
+		_, err := New(Config{Address: "https://127.0.0.1/json_rpc", TLS: conf}).GetHeight()
+		assert.Error(t, err)
+	}
+}
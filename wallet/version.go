@@ -0,0 +1,250 @@
+package wallet
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// RPCVersion is a monero-wallet-rpc RPC version, as returned packed by GetVersion.
+type RPCVersion struct {
+	Major uint32
+	Minor uint32
+}
+
+// ParseRPCVersion unpacks a version as returned by GetVersion,
+// Major being encoded over the high 16 bits and Minor over the low 16 bits.
+func ParseRPCVersion(packed uint64) RPCVersion {
+	return RPCVersion{
+		Major: uint32(packed >> 16),
+		Minor: uint32(packed & 0xffff),
+	}
+}
+
+// Packed returns the version packed as by GetVersion.
+func (v RPCVersion) Packed() uint64 {
+	return uint64(v.Major)<<16 | uint64(v.Minor)
+}
+
+// AtLeast tells whether v is o or a later version.
+func (v RPCVersion) AtLeast(o RPCVersion) bool {
+	if v.Major != o.Major {
+		return v.Major > o.Major
+	}
+	return v.Minor >= o.Minor
+}
+
+func (v RPCVersion) String() string {
+	return fmt.Sprintf("%d.%d", v.Major, v.Minor)
+}
+
+// RPCVersion returns the unpacked version.
+func (r *ResponseGetVersion) RPCVersion() RPCVersion {
+	return ParseRPCVersion(r.Version)
+}
+
+// ErrUnsupportedByServer is matched by an *UnsupportedError.
+var ErrUnsupportedByServer = errors.New("unsupported by server")
+
+// UnsupportedError is returned instead of sending a call the server is too old to understand.
+type UnsupportedError struct {
+	Method string
+	// Field is the JSON name of the unsupported request field, empty if the method is unsupported.
+	Field string
+	// Version is the version of the server, Required the first version supporting the call.
+	Version  RPCVersion
+	Required RPCVersion
+}
+
+func (e *UnsupportedError) Error() string {
+	what := e.Method
+	if e.Field != "" {
+		what += " field " + e.Field
+	}
+	return fmt.Sprintf("%v needs wallet-rpc %v, server is %v", what, e.Required, e.Version)
+}
+
+func (e *UnsupportedError) Is(target error) bool {
+	return target == ErrUnsupportedByServer
+}
+
+// methodVersions are the first wallet-rpc versions supporting methods added after
+// get_version. Methods not listed are supported by every version.
+var methodVersions = map[string]RPCVersion{
+	"get_address_index":            {1, 2},
+	"get_languages":                {1, 4},
+	"restore_deterministic_wallet": {1, 5},
+	"validate_address":             {1, 6},
+	"freeze":                       {1, 12},
+	"thaw":                         {1, 12},
+	"frozen":                       {1, 12},
+	"set_daemon":                   {1, 17},
+	"estimate_tx_size_and_weight":  {1, 18},
+	"get_default_fee_priority":     {1, 22},
+}
+
+// ringSizeVersion is the first version taking ring_size instead of mixin.
+var ringSizeVersion = RPCVersion{1, 0}
+
+// fieldVersions are the first wallet-rpc versions supporting request fields added
+// after their method, by method and JSON field name. Set fields are checked after
+// the request has been adapted.
+var fieldVersions = map[string]map[string]RPCVersion{
+	"transfer":       {"ring_size": ringSizeVersion},
+	"transfer_split": {"ring_size": ringSizeVersion},
+	"sweep_all":      {"ring_size": ringSizeVersion, "subaddr_indices_all": {1, 19}},
+	"sweep_single":   {"ring_size": ringSizeVersion},
+}
+
+// requestAdapters rewrite requests for the version of the server. They return
+// a modified copy of the request, or the request itself if it fits already.
+var requestAdapters = map[string]func(RPCVersion, interface{}) interface{}{
+	"transfer":       adaptRingSize,
+	"transfer_split": adaptRingSize,
+	"sweep_all":      adaptRingSize,
+	"sweep_single":   adaptRingSize,
+}
+
+// adaptRingSize turns ring_size into mixin (ring size - 1) for servers predating
+// ring_size, and mixin into ring_size for the others.
+func adaptRingSize(v RPCVersion, params interface{}) interface{} {
+	rv := reflect.ValueOf(params)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return params
+	}
+	cp := reflect.New(rv.Elem().Type())
+	cp.Elem().Set(rv.Elem())
+	mixin, ringSize := fieldByJSONName(cp.Elem(), "mixin"), fieldByJSONName(cp.Elem(), "ring_size")
+	if !mixin.IsValid() || !ringSize.IsValid() {
+		return params
+	}
+	if v.AtLeast(ringSizeVersion) {
+		if ringSize.Uint() != 0 || mixin.Uint() == 0 {
+			return params
+		}
+		ringSize.SetUint(mixin.Uint() + 1)
+		mixin.SetUint(0)
+	} else {
+		if ringSize.Uint() == 0 {
+			return params
+		}
+		mixin.SetUint(ringSize.Uint() - 1)
+		ringSize.SetUint(0)
+	}
+	return cp.Interface()
+}
+
+// fieldByJSONName returns the field of struct v with the given JSON name.
+func fieldByJSONName(v reflect.Value, name string) reflect.Value {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		if t.Field(i).PkgPath == "" && jsonName(t.Field(i)) == name {
+			return v.Field(i)
+		}
+	}
+	return reflect.Value{}
+}
+
+// Capabilities negotiates the version of monero-wallet-rpc with get_version on
+// the first call, then fails calls the server does not support with an
+// *UnsupportedError before sending them, and adapts requests to older servers
+// (eg. mixin instead of ring_size).
+//
+// Servers without get_version are considered to be of version 0.0.
+// A Capabilities is installed with its Interceptor.
+type Capabilities struct {
+	mu      sync.Mutex
+	known   bool
+	version RPCVersion
+}
+
+// NewCapabilities returns a Capabilities negotiating on first use.
+func NewCapabilities() *Capabilities {
+	return &Capabilities{}
+}
+
+// Version returns the negotiated version, and false if none was negotiated yet.
+func (c *Capabilities) Version() (RPCVersion, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.version, c.known
+}
+
+// Reset forgets the negotiated version, eg. after monero-wallet-rpc was upgraded.
+func (c *Capabilities) Reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.known = false
+}
+
+// Supports tells whether a server of version v supports method.
+func Supports(v RPCVersion, method string) bool {
+	required, ok := methodVersions[method]
+	return !ok || v.AtLeast(required)
+}
+
+// Interceptor returns the interceptor negotiating and checking calls.
+func (c *Capabilities) Interceptor() Interceptor {
+	return func(ctx context.Context, method string, params, result interface{}, next Invoker) error {
+		if method == "get_version" {
+			return next(ctx, method, params, result)
+		}
+		v, err := c.negotiate(ctx, next)
+		if err != nil {
+			return err
+		}
+		if !Supports(v, method) {
+			return &UnsupportedError{Method: method, Version: v, Required: methodVersions[method]}
+		}
+		if adapt, ok := requestAdapters[method]; ok {
+			params = adapt(v, params)
+		}
+		if err := checkFields(v, method, params); err != nil {
+			return err
+		}
+		return next(ctx, method, params, result)
+	}
+}
+
+// negotiate returns the version of the server, asking it on first use.
+// The lock is not held while asking: in a batch, next only returns once every
+// element reached the end of the chain, so elements waiting for the lock would
+// never let the batch be sent. Concurrent first calls each ask the version instead.
+func (c *Capabilities) negotiate(ctx context.Context, next Invoker) (RPCVersion, error) {
+	if v, ok := c.Version(); ok {
+		return v, nil
+	}
+	resp := &ResponseGetVersion{}
+	err := next(ctx, "get_version", nil, resp)
+	if err != nil && !errors.Is(err, ErrMethodNotFound) {
+		return RPCVersion{}, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.version = resp.RPCVersion()
+	c.known = true
+	return c.version, nil
+}
+
+// checkFields fails if params sets a field the server does not support.
+func checkFields(v RPCVersion, method string, params interface{}) error {
+	fields, ok := fieldVersions[method]
+	if !ok {
+		return nil
+	}
+	rv := reflect.ValueOf(params)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return nil
+	}
+	for name, required := range fields {
+		if v.AtLeast(required) {
+			continue
+		}
+		if f := fieldByJSONName(rv.Elem(), name); f.IsValid() && !f.IsZero() {
+			return &UnsupportedError{Method: method, Field: name, Version: v, Required: required}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,161 @@
+package wallet
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRPCVersion(t *testing.T) {
+	v := ParseRPCVersion(65562)
+	assert.Equal(t, RPCVersion{1, 26}, v)
+	assert.Equal(t, uint64(65562), v.Packed())
+	assert.Equal(t, "1.26", v.String())
+	assert.True(t, v.AtLeast(RPCVersion{1, 26}))
+	assert.True(t, v.AtLeast(RPCVersion{0, 40}))
+	assert.False(t, v.AtLeast(RPCVersion{1, 27}))
+	assert.False(t, v.AtLeast(RPCVersion{2, 0}))
+}
+
+// newVersionServer is a fake monero-wallet-rpc of the given version, recording
+// the params of the calls. A zero version does not know get_version.
+func newVersionServer(t *testing.T, version uint64, versionCalls *int32, params map[string]json.RawMessage) *httptest.Server {
+	return newTestServer(t, func(req *rpcRequest) interface{} {
+		switch {
+		case req.Method == "get_version" && version == 0:
+			return &WalletError{Code: ErrMethodNotFound, Message: "Method not found"}
+		case req.Method == "get_version":
+			atomic.AddInt32(versionCalls, 1)
+			return H{"version": version}
+		}
+		params[req.Method] = req.Params
+		return H{}
+	})
+}
+
+func TestCapabilities(t *testing.T) {
+	var versionCalls int32
+	params := map[string]json.RawMessage{}
+	srv := newVersionServer(t, RPCVersion{1, 5}.Packed(), &versionCalls, params)
+	caps := NewCapabilities()
+	cl := New(Config{Address: srv.URL, Interceptors: []Interceptor{caps.Interceptor()}})
+
+	_, ok := caps.Version()
+	assert.False(t, ok)
+
+	_, err := cl.ValidateAddress(&RequestValidateAddress{Address: "4..."})
+	assert.True(t, errors.Is(err, ErrUnsupportedByServer))
+	var uerr *UnsupportedError
+	assert.True(t, errors.As(err, &uerr))
+	assert.Equal(t, "validate_address", uerr.Method)
+	assert.Equal(t, RPCVersion{1, 6}, uerr.Required)
+	assert.NotContains(t, params, "validate_address")
+
+	_, err = cl.SweepAll(&RequestSweepAll{Address: "4...", SubaddrIndicesAll: true})
+	assert.True(t, errors.As(err, &uerr))
+	assert.Equal(t, "subaddr_indices_all", uerr.Field)
+
+	// mixin is sent as ring_size, without changing the request of the caller
+	req := &RequestTransfer{Mixing: 15}
+	_, err = cl.Transfer(req)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `16`, string(field(t, params["transfer"], "ring_size")))
+	assert.JSONEq(t, `0`, string(field(t, params["transfer"], "mixin")))
+	assert.Equal(t, uint64(15), req.Mixing)
+	assert.Equal(t, uint64(0), req.RingSize)
+
+	v, ok := caps.Version()
+	assert.True(t, ok)
+	assert.Equal(t, RPCVersion{1, 5}, v)
+	assert.Equal(t, int32(1), versionCalls)
+}
+
+func TestCapabilitiesLegacyServer(t *testing.T) {
+	var versionCalls int32
+	params := map[string]json.RawMessage{}
+	srv := newVersionServer(t, 0, &versionCalls, params)
+	caps := NewCapabilities()
+	cl := New(Config{Address: srv.URL, Interceptors: []Interceptor{caps.Interceptor()}})
+
+	_, err := cl.TransferSplit(&RequestTransferSplit{RingSize: 11})
+	assert.NoError(t, err)
+	assert.JSONEq(t, `10`, string(field(t, params["transfer_split"], "mixin")))
+	assert.Nil(t, field(t, params["transfer_split"], "ring_size"))
+
+	v, ok := caps.Version()
+	assert.True(t, ok)
+	assert.Equal(t, RPCVersion{}, v)
+}
+
+// field returns a field of a JSON-RPC params object, nil if missing.
+func field(t *testing.T, params json.RawMessage, name string) json.RawMessage {
+	fields := map[string]json.RawMessage{}
+	if err := json.Unmarshal(params, &fields); err != nil {
+		t.Fatal(err)
+	}
+	return fields[name]
+}
+
+func TestCapabilitiesBatch(t *testing.T) {
+	var batches int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var raw json.RawMessage
+		json.NewDecoder(r.Body).Decode(&raw)
+		var reqs []rpcRequest
+		batch := bytes.HasPrefix(raw, []byte("["))
+		if batch {
+			atomic.AddInt32(&batches, 1)
+			json.Unmarshal(raw, &reqs)
+		} else {
+			reqs = make([]rpcRequest, 1)
+			json.Unmarshal(raw, &reqs[0])
+		}
+		resps := []H{}
+		for _, req := range reqs {
+			result := H{"height": 5}
+			if req.Method == "get_version" {
+				result = H{"version": RPCVersion{1, 5}.Packed()}
+			}
+			resps = append(resps, H{"jsonrpc": "2.0", "id": req.ID, "result": result})
+		}
+		if !batch {
+			json.NewEncoder(w).Encode(resps[0])
+			return
+		}
+		json.NewEncoder(w).Encode(resps)
+	}))
+	defer srv.Close()
+	caps := NewCapabilities()
+	cl := NewContextClient(Config{Address: srv.URL, Interceptors: []Interceptor{caps.Interceptor()}})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	h1, h2 := &ResponseGetHeight{}, &ResponseGetHeight{}
+	elems := []*BatchElem{{Method: "get_height", Result: h1}, {Method: "get_height", Result: h2}}
+	assert.NoError(t, cl.BatchCallContext(ctx, elems))
+	assert.NoError(t, elems[0].Error)
+	assert.NoError(t, elems[1].Error)
+	assert.Equal(t, uint64(5), h2.Height)
+
+	// once negotiated, a batch is sent at once
+	atomic.StoreInt32(&batches, 0)
+	assert.NoError(t, cl.BatchCallContext(ctx, elems))
+	assert.Equal(t, int32(1), batches)
+}
+
+func TestSupports(t *testing.T) {
+	assert.True(t, Supports(RPCVersion{1, 0}, "get_balance"))
+	assert.False(t, Supports(RPCVersion{1, 11}, "freeze"))
+	assert.True(t, Supports(RPCVersion{1, 12}, "frozen"))
+	assert.False(t, Supports(RPCVersion{1, 4}, "restore_deterministic_wallet"))
+	assert.False(t, Supports(RPCVersion{1, 16}, "set_daemon"))
+	assert.True(t, Supports(RPCVersion{1, 22}, "get_default_fee_priority"))
+}